@@ -0,0 +1,27 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+)
+
+func TestBalanceGet(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/balance.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("balance.json")))
+
+	balance, err := client.Balance.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Balance.Get returned error: %v", err)
+	}
+
+	if len(balance) != 1 || balance[0].Currency != "USD" || !balance[0].Amount.Equals(decimal.NewFromFloat(53.99)) {
+		t.Errorf("Balance.Get returned %+v", balance)
+	}
+}
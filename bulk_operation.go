@@ -0,0 +1,509 @@
+package goshopify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	bulkOperationDefaultPollInterval    = time.Second
+	bulkOperationDefaultMaxPollInterval = 30 * time.Second
+
+	// maximum size of a single line of a bulk operation result
+	bulkOperationMaxLineSize = 16 * 1024 * 1024
+
+	bulkOperationFields = `id status errorCode createdAt completedAt objectCount fileSize url partialDataUrl query type`
+)
+
+// BulkOperationService is an interface for interfacing with Shopify's bulk
+// operations through the GraphQL API.
+// See: https://shopify.dev/docs/api/usage/bulk-operations/queries
+type BulkOperationService interface {
+	RunQuery(ctx context.Context, query string) (*BulkOperation, error)
+	RunMutation(ctx context.Context, mutation string, variables []interface{}) (*BulkOperation, error)
+	Current(ctx context.Context, opType BulkOperationType) (*BulkOperation, error)
+	Cancel(ctx context.Context, id string) (*BulkOperation, error)
+	Wait(ctx context.Context, opType BulkOperationType) (*BulkOperation, error)
+	Results(ctx context.Context, op *BulkOperation) (*BulkResultReader, error)
+}
+
+// BulkOperationServiceOp handles communication with the bulk operation
+// related methods of the Shopify GraphQL API.
+type BulkOperationServiceOp struct {
+	client *Client
+
+	// delays between polls of Wait, defaults to 1s doubling up to 30s
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+}
+
+// BulkOperationType is the type of a bulk operation
+type BulkOperationType string
+
+const (
+	BulkOperationTypeQuery    BulkOperationType = "QUERY"
+	BulkOperationTypeMutation BulkOperationType = "MUTATION"
+)
+
+// BulkOperationStatus is the status of a bulk operation
+type BulkOperationStatus string
+
+const (
+	BulkOperationStatusCreated   BulkOperationStatus = "CREATED"
+	BulkOperationStatusRunning   BulkOperationStatus = "RUNNING"
+	BulkOperationStatusCompleted BulkOperationStatus = "COMPLETED"
+	BulkOperationStatusCanceling BulkOperationStatus = "CANCELING"
+	BulkOperationStatusCanceled  BulkOperationStatus = "CANCELED"
+	BulkOperationStatusFailed    BulkOperationStatus = "FAILED"
+	BulkOperationStatusExpired   BulkOperationStatus = "EXPIRED"
+)
+
+// Done reports whether the operation reached a final status
+func (s BulkOperationStatus) Done() bool {
+	switch s {
+	case BulkOperationStatusCompleted, BulkOperationStatusCanceled, BulkOperationStatusFailed, BulkOperationStatusExpired:
+		return true
+	}
+	return false
+}
+
+// BulkOperation represents a Shopify bulk operation
+type BulkOperation struct {
+	Id             string              `json:"id"`
+	Status         BulkOperationStatus `json:"status"`
+	ErrorCode      string              `json:"errorCode"`
+	CreatedAt      *time.Time          `json:"createdAt"`
+	CompletedAt    *time.Time          `json:"completedAt"`
+	ObjectCount    string              `json:"objectCount"`
+	FileSize       string              `json:"fileSize"`
+	Url            string              `json:"url"`
+	PartialDataUrl string              `json:"partialDataUrl"`
+	Query          string              `json:"query"`
+	Type           BulkOperationType   `json:"type"`
+}
+
+// BulkOperationError is returned by Wait when an operation failed or expired
+type BulkOperationError struct {
+	Operation *BulkOperation
+}
+
+func (e BulkOperationError) Error() string {
+	if e.Operation.ErrorCode != "" {
+		return fmt.Sprintf("bulk operation %s %s: %s", e.Operation.Id, strings.ToLower(string(e.Operation.Status)), e.Operation.ErrorCode)
+	}
+	return fmt.Sprintf("bulk operation %s %s", e.Operation.Id, strings.ToLower(string(e.Operation.Status)))
+}
+
+type bulkOperationPayload struct {
+	BulkOperation *BulkOperation `json:"bulkOperation"`
+}
+
+type stagedUploadTarget struct {
+	Url        string `json:"url"`
+	Parameters []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"parameters"`
+}
+
+// RunQuery starts a bulk query, see Wait and Results to get its result
+func (s *BulkOperationServiceOp) RunQuery(ctx context.Context, query string) (*BulkOperation, error) {
+	m := fmt.Sprintf(`mutation bulkOperationRunQuery($query: String!) {
+		bulkOperationRunQuery(query: $query) {
+			bulkOperation { %s }
+			userErrors { field message }
+		}
+	}`, bulkOperationFields)
+
+	resp := struct {
+		BulkOperationRunQuery bulkOperationPayload `json:"bulkOperationRunQuery"`
+	}{}
+	err := GraphQLMutate(ctx, s.client.GraphQL, m, map[string]interface{}{"query": query}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.BulkOperationRunQuery.BulkOperation, nil
+}
+
+// RunMutation uploads variables, one entry per call of mutation, as a
+// JSONL file through a staged upload and starts the bulk mutation.
+func (s *BulkOperationServiceOp) RunMutation(ctx context.Context, mutation string, variables []interface{}) (*BulkOperation, error) {
+	vars := &bytes.Buffer{}
+	enc := json.NewEncoder(vars)
+	for _, v := range variables {
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	path, err := s.stageUpload(ctx, vars.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	m := fmt.Sprintf(`mutation bulkOperationRunMutation($mutation: String!, $stagedUploadPath: String!) {
+		bulkOperationRunMutation(mutation: $mutation, stagedUploadPath: $stagedUploadPath) {
+			bulkOperation { %s }
+			userErrors { field message }
+		}
+	}`, bulkOperationFields)
+
+	resp := struct {
+		BulkOperationRunMutation bulkOperationPayload `json:"bulkOperationRunMutation"`
+	}{}
+	err = GraphQLMutate(ctx, s.client.GraphQL, m, map[string]interface{}{
+		"mutation":         mutation,
+		"stagedUploadPath": path,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.BulkOperationRunMutation.BulkOperation, nil
+}
+
+// stageUpload uploads the variables file of a bulk mutation and returns its
+// staged upload path.
+func (s *BulkOperationServiceOp) stageUpload(ctx context.Context, file []byte) (string, error) {
+	m := `mutation stagedUploadsCreate($input: [StagedUploadInput!]!) {
+		stagedUploadsCreate(input: $input) {
+			stagedTargets { url resourceUrl parameters { name value } }
+			userErrors { field message }
+		}
+	}`
+	input := []map[string]interface{}{{
+		"resource":   "BULK_MUTATION_VARIABLES",
+		"filename":   "bulk_op_vars",
+		"mimeType":   "text/jsonl",
+		"httpMethod": "POST",
+	}}
+
+	resp := struct {
+		StagedUploadsCreate struct {
+			StagedTargets []stagedUploadTarget `json:"stagedTargets"`
+		} `json:"stagedUploadsCreate"`
+	}{}
+	err := GraphQLMutate(ctx, s.client.GraphQL, m, map[string]interface{}{"input": input}, &resp)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.StagedUploadsCreate.StagedTargets) == 0 {
+		return "", errors.New("bulk operation: no staged upload target returned")
+	}
+	target := resp.StagedUploadsCreate.StagedTargets[0]
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	var path string
+	for _, p := range target.Parameters {
+		if p.Name == "key" {
+			path = p.Value
+		}
+		if err := w.WriteField(p.Name, p.Value); err != nil {
+			return "", err
+		}
+	}
+	part, err := w.CreateFormFile("file", "bulk_op_vars")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(file); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target.Url, body)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	// the upload goes to cloud storage, not through the API middleware
+	res, err := s.transferClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return "", ResponseError{Status: res.StatusCode, Message: fmt.Sprintf("bulk operation: staged upload failed with status %d", res.StatusCode)}
+	}
+
+	if path == "" {
+		return "", errors.New("bulk operation: staged upload target has no key")
+	}
+	return path, nil
+}
+
+// transferClient returns the HTTP client of the API without its timeout,
+// which also covers reading the body, for uploads and downloads of files
+// that take longer. They are canceled through their context instead.
+func (s *BulkOperationServiceOp) transferClient() *http.Client {
+	c := *s.client.Client
+	c.Timeout = 0
+	return &c
+}
+
+// Current returns the latest bulk operation of the given type, or nil if
+// the shop never ran one.
+func (s *BulkOperationServiceOp) Current(ctx context.Context, opType BulkOperationType) (*BulkOperation, error) {
+	q := fmt.Sprintf(`query currentBulkOperation($type: BulkOperationType!) {
+		currentBulkOperation(type: $type) { %s }
+	}`, bulkOperationFields)
+
+	resp := struct {
+		CurrentBulkOperation *BulkOperation `json:"currentBulkOperation"`
+	}{}
+	err := s.client.GraphQL.Query(ctx, q, map[string]interface{}{"type": opType}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.CurrentBulkOperation, nil
+}
+
+// Cancel requests the cancellation of a running bulk operation
+func (s *BulkOperationServiceOp) Cancel(ctx context.Context, id string) (*BulkOperation, error) {
+	m := fmt.Sprintf(`mutation bulkOperationCancel($id: ID!) {
+		bulkOperationCancel(id: $id) {
+			bulkOperation { %s }
+			userErrors { field message }
+		}
+	}`, bulkOperationFields)
+
+	resp := struct {
+		BulkOperationCancel bulkOperationPayload `json:"bulkOperationCancel"`
+	}{}
+	err := GraphQLMutate(ctx, s.client.GraphQL, m, map[string]interface{}{"id": id}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.BulkOperationCancel.BulkOperation, nil
+}
+
+// Wait polls the current bulk operation of the given type with exponential
+// backoff until it is done. A failed or expired operation is returned along
+// with a BulkOperationError.
+func (s *BulkOperationServiceOp) Wait(ctx context.Context, opType BulkOperationType) (*BulkOperation, error) {
+	interval := s.pollInterval
+	if interval <= 0 {
+		interval = bulkOperationDefaultPollInterval
+	}
+	maxInterval := s.maxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = bulkOperationDefaultMaxPollInterval
+	}
+
+	for {
+		op, err := s.Current(ctx, opType)
+		if err != nil {
+			return nil, err
+		}
+		if op == nil {
+			return nil, fmt.Errorf("bulk operation: no %s operation found", strings.ToLower(string(opType)))
+		}
+
+		if op.Status.Done() {
+			if op.Status == BulkOperationStatusFailed || op.Status == BulkOperationStatusExpired {
+				return op, BulkOperationError{Operation: op}
+			}
+			return op, nil
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// Results downloads the JSONL result of a completed operation, or the
+// partial data of a failed one. The returned reader must be closed. The
+// download is not bound by the timeout of the client, ctx cancels it.
+func (s *BulkOperationServiceOp) Results(ctx context.Context, op *BulkOperation) (*BulkResultReader, error) {
+	url := op.Url
+	if url == "" {
+		url = op.PartialDataUrl
+	}
+	if url == "" {
+		// operations without any result have no file
+		return newBulkResultReader(ioutil.NopCloser(strings.NewReader(""))), nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	res, err := s.transferClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusMultipleChoices {
+		res.Body.Close()
+		return nil, ResponseError{Status: res.StatusCode, Message: fmt.Sprintf("bulk operation: result download failed with status %d", res.StatusCode)}
+	}
+
+	return newBulkResultReader(res.Body), nil
+}
+
+// BulkResultReader streams the objects of a bulk operation result. Child
+// objects, recognised by their __parentId, are nested into their parent
+// under a list named after their type: the type of their GraphQL id, e.g.
+// "LineItem" for gid://shopify/LineItem/1, else their __typename, else
+// "__children". Shopify writes children after their parent, so a root
+// object is complete once the next root object starts.
+//
+//	for r.Next() {
+//		var order struct {
+//			Id        string `json:"id"`
+//			LineItem []struct {
+//				Id string `json:"id"`
+//			} `json:"LineItem"`
+//		}
+//		if err := r.Decode(&order); err != nil {
+//			// handle error
+//		}
+//	}
+//	if err := r.Err(); err != nil {
+//		// handle error
+//	}
+type BulkResultReader struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+
+	// root object being assembled and the objects of its tree by id
+	pending map[string]interface{}
+	index   map[string]map[string]interface{}
+
+	current map[string]interface{}
+	err     error
+}
+
+func newBulkResultReader(body io.ReadCloser) *BulkResultReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), bulkOperationMaxLineSize)
+	return &BulkResultReader{body: body, scanner: scanner}
+}
+
+// Next advances to the next root object, returning false at the end of the
+// result or on error, see Err.
+func (r *BulkResultReader) Next() bool {
+	r.current = nil
+	if r.err != nil {
+		return false
+	}
+
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var obj map[string]interface{}
+		if err := json.Unmarshal(line, &obj); err != nil {
+			r.err = err
+			return false
+		}
+
+		parentId, isChild := obj["__parentId"].(string)
+		if !isChild {
+			done := r.pending
+			r.startRoot(obj)
+			if done != nil {
+				r.current = done
+				return true
+			}
+			continue
+		}
+
+		parent, ok := r.index[parentId]
+		if !ok {
+			r.err = fmt.Errorf("bulk operation: parent %s of line not found", parentId)
+			return false
+		}
+		delete(obj, "__parentId")
+		key := bulkObjectType(obj)
+		children, _ := parent[key].([]interface{})
+		parent[key] = append(children, obj)
+		if id, ok := obj["id"].(string); ok {
+			r.index[id] = obj
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		r.err = err
+		return false
+	}
+
+	if r.pending != nil {
+		r.current = r.pending
+		r.pending = nil
+		return true
+	}
+	return false
+}
+
+func (r *BulkResultReader) startRoot(obj map[string]interface{}) {
+	r.pending = obj
+	r.index = make(map[string]map[string]interface{})
+	if id, ok := obj["id"].(string); ok {
+		r.index[id] = obj
+	}
+}
+
+// Object returns the current root object with its children nested
+func (r *BulkResultReader) Object() map[string]interface{} {
+	return r.current
+}
+
+// Decode unmarshals the current root object with its children into v
+func (r *BulkResultReader) Decode(v interface{}) error {
+	if r.current == nil {
+		return errors.New("bulk operation: no current object")
+	}
+	b, err := json.Marshal(r.current)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Err returns the error that stopped Next, if any
+func (r *BulkResultReader) Err() error {
+	return r.err
+}
+
+// Close closes the underlying result download
+func (r *BulkResultReader) Close() error {
+	return r.body.Close()
+}
+
+// bulkObjectType returns the key a child object is nested under
+func bulkObjectType(obj map[string]interface{}) string {
+	if id, ok := obj["id"].(string); ok && strings.HasPrefix(id, "gid://") {
+		// gid://shopify/LineItem/1
+		parts := strings.Split(strings.TrimPrefix(id, "gid://"), "/")
+		if len(parts) >= 3 {
+			return parts[1]
+		}
+	}
+	if t, ok := obj["__typename"].(string); ok && t != "" {
+		return t
+	}
+	return "__children"
+}
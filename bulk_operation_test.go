@@ -0,0 +1,398 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func decodeGraphQLRequest(t *testing.T, req *http.Request) graphQLRequest {
+	t.Helper()
+	var gr graphQLRequest
+	if err := json.NewDecoder(req.Body).Decode(&gr); err != nil {
+		t.Fatalf("invalid GraphQL request: %v", err)
+	}
+	return gr
+}
+
+func TestBulkOperationRunQuery(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			gr := decodeGraphQLRequest(t, req)
+			if !strings.Contains(gr.Query, "bulkOperationRunQuery") || gr.Variables["query"] != "{ orders { edges { node { id } } } }" {
+				t.Errorf("BulkOperation.RunQuery sent %+v", gr)
+			}
+			return httpmock.NewStringResponse(200, `{"data":{"bulkOperationRunQuery":{
+				"bulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"CREATED"},
+				"userErrors":[]
+			}}}`), nil
+		})
+
+	op, err := client.BulkOperation.RunQuery(context.Background(), "{ orders { edges { node { id } } } }")
+	if err != nil {
+		t.Fatalf("BulkOperation.RunQuery returned error: %v", err)
+	}
+
+	expected := &BulkOperation{Id: "gid://shopify/BulkOperation/1", Status: BulkOperationStatusCreated}
+	if !reflect.DeepEqual(op, expected) {
+		t.Errorf("BulkOperation.RunQuery returned %+v, expected %+v", op, expected)
+	}
+}
+
+func TestBulkOperationRunQueryUserErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"bulkOperationRunQuery":{
+			"bulkOperation":null,
+			"userErrors":[{"field":["query"],"message":"A bulk query operation for this app and shop is already in progress"}]
+		}}}`))
+
+	_, err := client.BulkOperation.RunQuery(context.Background(), "{ orders { edges { node { id } } } }")
+
+	var userErrors UserErrors
+	if !errors.As(err, &userErrors) || len(userErrors) != 1 {
+		t.Errorf("BulkOperation.RunQuery returned %#v, expected UserErrors", err)
+	}
+}
+
+func TestBulkOperationRunMutation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			gr := decodeGraphQLRequest(t, req)
+			switch {
+			case strings.Contains(gr.Query, "stagedUploadsCreate"):
+				return httpmock.NewStringResponse(200, `{"data":{"stagedUploadsCreate":{
+					"stagedTargets":[{
+						"url":"https://shopify-staged-uploads.storage.googleapis.com/",
+						"parameters":[{"name":"key","value":"tmp/1/bulk/vars"},{"name":"policy","value":"abc"}]
+					}],
+					"userErrors":[]
+				}}}`), nil
+			case strings.Contains(gr.Query, "bulkOperationRunMutation"):
+				if gr.Variables["stagedUploadPath"] != "tmp/1/bulk/vars" {
+					t.Errorf("BulkOperation.RunMutation sent staged upload path %v", gr.Variables["stagedUploadPath"])
+				}
+				return httpmock.NewStringResponse(200, `{"data":{"bulkOperationRunMutation":{
+					"bulkOperation":{"id":"gid://shopify/BulkOperation/2","status":"CREATED","type":"MUTATION"},
+					"userErrors":[]
+				}}}`), nil
+			}
+			t.Errorf("unexpected GraphQL request %s", gr.Query)
+			return httpmock.NewStringResponse(400, ""), nil
+		})
+
+	var uploaded string
+	httpmock.RegisterResponder("POST", "https://shopify-staged-uploads.storage.googleapis.com/",
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Shopify-Access-Token") != "" {
+				t.Errorf("staged upload sent the access token")
+			}
+			if req.FormValue("policy") != "abc" {
+				t.Errorf("staged upload policy %q, expected abc", req.FormValue("policy"))
+			}
+			f, _, err := req.FormFile("file")
+			if err != nil {
+				t.Fatalf("staged upload has no file: %v", err)
+			}
+			b, _ := ioutil.ReadAll(f)
+			uploaded = string(b)
+			return httpmock.NewStringResponse(201, ""), nil
+		})
+
+	variables := []interface{}{
+		map[string]interface{}{"input": map[string]interface{}{"title": "Shirt"}},
+		map[string]interface{}{"input": map[string]interface{}{"title": "Hat"}},
+	}
+	op, err := client.BulkOperation.RunMutation(context.Background(), "mutation call($input: ProductInput!) { productCreate(input: $input) { product { id } } }", variables)
+	if err != nil {
+		t.Fatalf("BulkOperation.RunMutation returned error: %v", err)
+	}
+
+	if op.Id != "gid://shopify/BulkOperation/2" || op.Type != BulkOperationTypeMutation {
+		t.Errorf("BulkOperation.RunMutation returned %+v", op)
+	}
+
+	expectedVars := "{\"input\":{\"title\":\"Shirt\"}}\n{\"input\":{\"title\":\"Hat\"}}\n"
+	if uploaded != expectedVars {
+		t.Errorf("BulkOperation.RunMutation uploaded %q, expected %q", uploaded, expectedVars)
+	}
+}
+
+func TestBulkOperationWait(t *testing.T) {
+	setup()
+	defer teardown()
+
+	statuses := []string{"CREATED", "RUNNING", "COMPLETED"}
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			gr := decodeGraphQLRequest(t, req)
+			if gr.Variables["type"] != "QUERY" {
+				t.Errorf("BulkOperation.Wait polled type %v", gr.Variables["type"])
+			}
+			status := statuses[0]
+			statuses = statuses[1:]
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"data":{"currentBulkOperation":{
+				"id":"gid://shopify/BulkOperation/1","status":"%s","objectCount":"7","url":"https://storage.googleapis.com/result.jsonl"
+			}}}`, status)), nil
+		})
+
+	bulk := client.BulkOperation.(*BulkOperationServiceOp)
+	bulk.pollInterval = time.Millisecond
+	bulk.maxPollInterval = 2 * time.Millisecond
+
+	op, err := bulk.Wait(context.Background(), BulkOperationTypeQuery)
+	if err != nil {
+		t.Fatalf("BulkOperation.Wait returned error: %v", err)
+	}
+
+	if op.Status != BulkOperationStatusCompleted || op.ObjectCount != "7" {
+		t.Errorf("BulkOperation.Wait returned %+v", op)
+	}
+	if len(statuses) != 0 {
+		t.Errorf("BulkOperation.Wait stopped polling early, %d polls left", len(statuses))
+	}
+}
+
+func TestBulkOperationWaitFailed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"currentBulkOperation":{
+			"id":"gid://shopify/BulkOperation/1","status":"FAILED","errorCode":"TIMEOUT"
+		}}}`))
+
+	op, err := client.BulkOperation.Wait(context.Background(), BulkOperationTypeQuery)
+
+	var opErr BulkOperationError
+	if !errors.As(err, &opErr) || opErr.Operation.ErrorCode != "TIMEOUT" {
+		t.Fatalf("BulkOperation.Wait returned %#v, expected a BulkOperationError", err)
+	}
+	if op == nil || op.Status != BulkOperationStatusFailed {
+		t.Errorf("BulkOperation.Wait returned %+v", op)
+	}
+
+	expectedError := "bulk operation gid://shopify/BulkOperation/1 failed: TIMEOUT"
+	if err.Error() != expectedError {
+		t.Errorf("BulkOperation.Wait returned error message %s but expected %s", err.Error(), expectedError)
+	}
+}
+
+func TestBulkOperationWaitContextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"currentBulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"RUNNING"}}}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.BulkOperation.Wait(ctx, BulkOperationTypeQuery)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("BulkOperation.Wait returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestBulkOperationCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			gr := decodeGraphQLRequest(t, req)
+			if gr.Variables["id"] != "gid://shopify/BulkOperation/1" {
+				t.Errorf("BulkOperation.Cancel sent id %v", gr.Variables["id"])
+			}
+			return httpmock.NewStringResponse(200, `{"data":{"bulkOperationCancel":{
+				"bulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"CANCELING"},
+				"userErrors":[]
+			}}}`), nil
+		})
+
+	op, err := client.BulkOperation.Cancel(context.Background(), "gid://shopify/BulkOperation/1")
+	if err != nil {
+		t.Fatalf("BulkOperation.Cancel returned error: %v", err)
+	}
+	if op.Status != BulkOperationStatusCanceling {
+		t.Errorf("BulkOperation.Cancel returned %+v", op)
+	}
+}
+
+func TestBulkOperationResults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", "https://storage.googleapis.com/result.jsonl",
+		httpmock.NewBytesResponder(200, loadFixture("bulk_operation_result.jsonl")))
+
+	r, err := client.BulkOperation.Results(context.Background(), &BulkOperation{Url: "https://storage.googleapis.com/result.jsonl"})
+	if err != nil {
+		t.Fatalf("BulkOperation.Results returned error: %v", err)
+	}
+	defer r.Close()
+
+	type order struct {
+		Id       string `json:"id"`
+		Name     string `json:"name"`
+		LineItem []struct {
+			Id          string `json:"id"`
+			Title       string `json:"title"`
+			Fulfillment []struct {
+				Status string `json:"status"`
+			} `json:"Fulfillment"`
+		} `json:"LineItem"`
+	}
+
+	var orders []order
+	for r.Next() {
+		var o order
+		if err := r.Decode(&o); err != nil {
+			t.Fatalf("BulkResultReader.Decode returned error: %v", err)
+		}
+		orders = append(orders, o)
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("BulkResultReader.Err returned %v", err)
+	}
+
+	if len(orders) != 3 {
+		t.Fatalf("BulkResultReader returned %d objects, expected 3", len(orders))
+	}
+	if orders[0].Name != "#1001" || len(orders[0].LineItem) != 2 || orders[0].LineItem[1].Title != "Hat" {
+		t.Errorf("BulkResultReader returned %+v", orders[0])
+	}
+	if len(orders[1].LineItem) != 1 || len(orders[1].LineItem[0].Fulfillment) != 1 || orders[1].LineItem[0].Fulfillment[0].Status != "SUCCESS" {
+		t.Errorf("BulkResultReader returned %+v", orders[1])
+	}
+	if orders[2].Name != "#1003" || len(orders[2].LineItem) != 0 {
+		t.Errorf("BulkResultReader returned %+v", orders[2])
+	}
+}
+
+// slowBody is a response body writing a line every delay, like a large
+// result file streamed from storage, aborted when ctx is done.
+type slowBody struct {
+	ctx   context.Context
+	lines []string
+	delay time.Duration
+}
+
+func (b *slowBody) Read(p []byte) (int, error) {
+	if len(b.lines) == 0 {
+		return 0, io.EOF
+	}
+	select {
+	case <-time.After(b.delay):
+	case <-b.ctx.Done():
+		return 0, b.ctx.Err()
+	}
+	n := copy(p, b.lines[0])
+	b.lines[0] = b.lines[0][n:]
+	if b.lines[0] == "" {
+		b.lines = b.lines[1:]
+	}
+	return n, nil
+}
+
+func (b *slowBody) Close() error {
+	return nil
+}
+
+func TestBulkOperationResultsSlowBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// reading the whole result takes longer than the timeout of API calls
+	client.Client.Timeout = 50 * time.Millisecond
+
+	httpmock.RegisterResponder("GET", "https://storage.googleapis.com/result.jsonl",
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, "")
+			resp.Body = &slowBody{
+				ctx:   req.Context(),
+				lines: []string{"{\"id\":\"gid://shopify/Order/1\"}\n", "{\"id\":\"gid://shopify/Order/2\"}\n", "{\"id\":\"gid://shopify/Order/3\"}\n"},
+				delay: 30 * time.Millisecond,
+			}
+			return resp, nil
+		})
+
+	r, err := client.BulkOperation.Results(context.Background(), &BulkOperation{Url: "https://storage.googleapis.com/result.jsonl"})
+	if err != nil {
+		t.Fatalf("BulkOperation.Results returned error: %v", err)
+	}
+	defer r.Close()
+
+	count := 0
+	for r.Next() {
+		count++
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("BulkResultReader.Err returned %v", err)
+	}
+	if count != 3 {
+		t.Errorf("BulkResultReader returned %d objects, expected 3", count)
+	}
+
+	// the context still cancels the download
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Millisecond)
+	defer cancel()
+	r, err = client.BulkOperation.Results(ctx, &BulkOperation{Url: "https://storage.googleapis.com/result.jsonl"})
+	if err != nil {
+		t.Fatalf("BulkOperation.Results returned error: %v", err)
+	}
+	defer r.Close()
+	for r.Next() {
+	}
+	if err := r.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("BulkResultReader.Err returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestBulkResultReaderMissingParent(t *testing.T) {
+	body := ioutil.NopCloser(strings.NewReader(`{"id":"gid://shopify/LineItem/1","__parentId":"gid://shopify/Order/1"}`))
+	r := newBulkResultReader(body)
+
+	if r.Next() {
+		t.Errorf("BulkResultReader.Next returned an object without its parent")
+	}
+	if r.Err() == nil {
+		t.Errorf("BulkResultReader.Err expected an error")
+	}
+}
+
+func TestBulkOperationResultsEmpty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	r, err := client.BulkOperation.Results(context.Background(), &BulkOperation{Status: BulkOperationStatusCompleted})
+	if err != nil {
+		t.Fatalf("BulkOperation.Results returned error: %v", err)
+	}
+	if r.Next() {
+		t.Errorf("BulkResultReader.Next returned an object for an empty result")
+	}
+}
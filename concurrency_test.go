@@ -0,0 +1,123 @@
+package goshopify
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// TestClientConcurrentUse hammers every service of a single client from many
+// goroutines. Run it with -race to detect unsynchronized client state.
+func TestClientConcurrentUse(t *testing.T) {
+	c := MustNewClient(app, "fooshop", "abcd", WithRetry(maxRetries))
+	httpmock.ActivateNonDefault(c.Client)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, `{}`)
+		resp.Header.Set("X-Shopify-Shop-Api-Call-Limit", "1/40")
+		resp.Header.Set("X-Shopify-API-Version", testApiVersion)
+		return resp, nil
+	})
+
+	calls := map[string]func(context.Context) error{
+		"AbandonedCheckout": func(ctx context.Context) error { _, err := c.AbandonedCheckout.List(ctx, nil); return err },
+		"AccessScopes":      func(ctx context.Context) error { _, err := c.AccessScopes.List(ctx, nil); return err },
+		"ApiPermissions":    func(ctx context.Context) error { return c.ApiPermissions.Delete(ctx) },
+		"ApplicationCharge": func(ctx context.Context) error { _, err := c.ApplicationCharge.List(ctx, nil); return err },
+		"Article":           func(ctx context.Context) error { _, err := c.Article.List(ctx, 1, nil); return err },
+		"Asset":             func(ctx context.Context) error { _, err := c.Asset.List(ctx, 1, nil); return err },
+		"AssignedFulfillmentOrder": func(ctx context.Context) error {
+			_, err := c.AssignedFulfillmentOrder.Get(ctx, nil)
+			return err
+		},
+		"Balance": func(ctx context.Context) error { _, err := c.Balance.Get(ctx); return err },
+		"Blog":    func(ctx context.Context) error { _, err := c.Blog.List(ctx, nil); return err },
+		"BulkOperation": func(ctx context.Context) error {
+			_, err := c.BulkOperation.Current(ctx, BulkOperationTypeQuery)
+			return err
+		},
+		"CarrierService":   func(ctx context.Context) error { _, err := c.CarrierService.List(ctx); return err },
+		"Collect":          func(ctx context.Context) error { _, err := c.Collect.List(ctx, nil); return err },
+		"Collection":       func(ctx context.Context) error { _, err := c.Collection.Get(ctx, 1, nil); return err },
+		"Country":          func(ctx context.Context) error { _, err := c.Country.List(ctx, nil); return err },
+		"CustomCollection": func(ctx context.Context) error { _, err := c.CustomCollection.List(ctx, nil); return err },
+		"Customer":         func(ctx context.Context) error { _, err := c.Customer.List(ctx, nil); return err },
+		"CustomerAddress":  func(ctx context.Context) error { _, err := c.CustomerAddress.List(ctx, 1, nil); return err },
+		"DiscountCode":     func(ctx context.Context) error { _, err := c.DiscountCode.List(ctx, 1); return err },
+		"Dispute":          func(ctx context.Context) error { _, err := c.Dispute.List(ctx, nil); return err },
+		"DraftOrder":       func(ctx context.Context) error { _, err := c.DraftOrder.List(ctx, nil); return err },
+		"Event":            func(ctx context.Context) error { _, err := c.Event.List(ctx, nil); return err },
+		"Fulfillment":      func(ctx context.Context) error { _, err := c.Fulfillment.List(ctx, nil); return err },
+		"FulfillmentEvent": func(ctx context.Context) error { _, err := c.FulfillmentEvent.List(ctx, 1, 1); return err },
+		"FulfillmentOrder": func(ctx context.Context) error { _, err := c.FulfillmentOrder.List(ctx, 1, nil); return err },
+		"FulfillmentRequest": func(ctx context.Context) error {
+			_, err := c.FulfillmentRequest.Send(ctx, 1, FulfillmentRequest{})
+			return err
+		},
+		"FulfillmentService":         func(ctx context.Context) error { _, err := c.FulfillmentService.List(ctx, nil); return err },
+		"GiftCard":                   func(ctx context.Context) error { _, err := c.GiftCard.Get(ctx, 1); return err },
+		"GraphQL":                    func(ctx context.Context) error { return c.GraphQL.Query(ctx, "query {}", nil, nil) },
+		"Image":                      func(ctx context.Context) error { _, err := c.Image.List(ctx, 1, nil); return err },
+		"InventoryItem":              func(ctx context.Context) error { _, err := c.InventoryItem.List(ctx, nil); return err },
+		"InventoryLevel":             func(ctx context.Context) error { _, err := c.InventoryLevel.List(ctx, nil); return err },
+		"Location":                   func(ctx context.Context) error { _, err := c.Location.List(ctx, nil); return err },
+		"Metafield":                  func(ctx context.Context) error { _, err := c.Metafield.List(ctx, nil); return err },
+		"Order":                      func(ctx context.Context) error { _, err := c.Order.List(ctx, nil); return err },
+		"OrderRisk":                  func(ctx context.Context) error { _, err := c.OrderRisk.List(ctx, 1, nil); return err },
+		"Page":                       func(ctx context.Context) error { _, err := c.Page.List(ctx, nil); return err },
+		"PaymentsTransactions":       func(ctx context.Context) error { _, err := c.PaymentsTransactions.List(ctx, nil); return err },
+		"Payouts":                    func(ctx context.Context) error { _, err := c.Payouts.List(ctx, nil); return err },
+		"PriceRule":                  func(ctx context.Context) error { _, err := c.PriceRule.Get(ctx, 1); return err },
+		"Product":                    func(ctx context.Context) error { _, err := c.Product.List(ctx, nil); return err },
+		"ProductListing":             func(ctx context.Context) error { _, err := c.ProductListing.List(ctx, nil); return err },
+		"Province":                   func(ctx context.Context) error { _, err := c.Province.List(ctx, 1, nil); return err },
+		"RecurringApplicationCharge": func(ctx context.Context) error { _, err := c.RecurringApplicationCharge.List(ctx, nil); return err },
+		"Redirect":                   func(ctx context.Context) error { _, err := c.Redirect.List(ctx, nil); return err },
+		"Refund":                     func(ctx context.Context) error { _, err := c.Refund.List(ctx, 1, nil); return err },
+		"ScriptTag":                  func(ctx context.Context) error { _, err := c.ScriptTag.List(ctx, nil); return err },
+		"ShippingZone":               func(ctx context.Context) error { _, err := c.ShippingZone.List(ctx); return err },
+		"Shop":                       func(ctx context.Context) error { _, err := c.Shop.Get(ctx, nil); return err },
+		"SmartCollection":            func(ctx context.Context) error { _, err := c.SmartCollection.List(ctx, nil); return err },
+		"StorefrontAccessToken":      func(ctx context.Context) error { _, err := c.StorefrontAccessToken.List(ctx, nil); return err },
+		"TenderTransaction":          func(ctx context.Context) error { _, err := c.TenderTransaction.List(ctx, nil); return err },
+		"Theme":                      func(ctx context.Context) error { _, err := c.Theme.List(ctx, nil); return err },
+		"Transaction":                func(ctx context.Context) error { _, err := c.Transaction.List(ctx, 1, nil); return err },
+		"UsageCharge":                func(ctx context.Context) error { _, err := c.UsageCharge.List(ctx, 1, nil); return err },
+		"Variant":                    func(ctx context.Context) error { _, err := c.Variant.List(ctx, 1, nil); return err },
+		"Webhook":                    func(ctx context.Context) error { _, err := c.Webhook.List(ctx, nil); return err },
+	}
+
+	const perService = 10
+
+	var wg sync.WaitGroup
+	for name, call := range calls {
+		for i := 0; i < perService; i++ {
+			wg.Add(1)
+			go func(name string, call func(context.Context) error) {
+				defer wg.Done()
+				if err := call(context.Background()); err != nil {
+					t.Errorf("%s: %v", name, err)
+				}
+				_ = c.RateLimitSnapshot()
+				_ = c.ApiVersion()
+			}(name, call)
+		}
+	}
+	wg.Wait()
+
+	if n := httpmock.GetTotalCallCount(); n != len(calls)*perService {
+		t.Errorf("made %d calls, expected %d", n, len(calls)*perService)
+	}
+
+	if v := c.ApiVersion(); v != testApiVersion {
+		t.Errorf("Client.ApiVersion() = %s, expected %s", v, testApiVersion)
+	}
+
+	if limits := c.RateLimitSnapshot(); limits.RequestCount != 1 || limits.BucketSize != 40 {
+		t.Errorf("Client.RateLimitSnapshot() = %#v, expected 1/40", limits)
+	}
+}
@@ -0,0 +1,86 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+)
+
+const countriesBasePath = "countries"
+
+// CountryService is an interface for interfacing with the country endpoints
+// of the Shopify API.
+// See: https://shopify.dev/docs/api/admin-rest/latest/resources/country
+type CountryService interface {
+	List(context.Context, interface{}) ([]Country, error)
+	Count(context.Context, interface{}) (int, error)
+	Get(context.Context, uint64, interface{}) (*Country, error)
+	Create(context.Context, Country) (*Country, error)
+	Update(context.Context, Country) (*Country, error)
+	Delete(context.Context, uint64) error
+}
+
+// CountryServiceOp handles communication with the country related methods of
+// the Shopify API.
+type CountryServiceOp struct {
+	client *Client
+}
+
+// Country is a country the shop charges taxes in, the same type as the
+// countries of a shipping zone. Tax is the country's tax rate, e.g. 0.05 for
+// 5%.
+type Country = ShippingCountry
+
+// CountryResource represents the result from the countries/X.json endpoint
+type CountryResource struct {
+	Country *Country `json:"country"`
+}
+
+// CountriesResource represents the result from the countries.json endpoint
+type CountriesResource struct {
+	Countries []Country `json:"countries"`
+}
+
+// List countries
+func (s *CountryServiceOp) List(ctx context.Context, options interface{}) ([]Country, error) {
+	path := fmt.Sprintf("%s.json", countriesBasePath)
+	resource := new(CountriesResource)
+	err := s.client.Get(ctx, path, resource, options)
+	return resource.Countries, err
+}
+
+// Count countries
+func (s *CountryServiceOp) Count(ctx context.Context, options interface{}) (int, error) {
+	path := fmt.Sprintf("%s/count.json", countriesBasePath)
+	return s.client.Count(ctx, path, options)
+}
+
+// Get individual country
+func (s *CountryServiceOp) Get(ctx context.Context, countryId uint64, options interface{}) (*Country, error) {
+	path := fmt.Sprintf("%s/%d.json", countriesBasePath, countryId)
+	resource := new(CountryResource)
+	err := s.client.Get(ctx, path, resource, options)
+	return resource.Country, err
+}
+
+// Create a new country, given its code and optionally its tax rate
+func (s *CountryServiceOp) Create(ctx context.Context, country Country) (*Country, error) {
+	path := fmt.Sprintf("%s.json", countriesBasePath)
+	wrappedData := CountryResource{Country: &country}
+	resource := new(CountryResource)
+	err := s.client.Post(ctx, path, wrappedData, resource)
+	return resource.Country, err
+}
+
+// Update an existing country
+func (s *CountryServiceOp) Update(ctx context.Context, country Country) (*Country, error) {
+	path := fmt.Sprintf("%s/%d.json", countriesBasePath, country.Id)
+	wrappedData := CountryResource{Country: &country}
+	resource := new(CountryResource)
+	err := s.client.Put(ctx, path, wrappedData, resource)
+	return resource.Country, err
+}
+
+// Delete an existing country
+func (s *CountryServiceOp) Delete(ctx context.Context, countryId uint64) error {
+	return s.client.Delete(ctx, fmt.Sprintf("%s/%d.json", countriesBasePath, countryId))
+}
@@ -0,0 +1,143 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+)
+
+func countryTests(t *testing.T, country Country) {
+	expectedId := uint64(879921427)
+	if country.Id != expectedId {
+		t.Errorf("Country.Id returned %+v, expected %+v", country.Id, expectedId)
+	}
+
+	if country.Code != "CA" || country.TaxName != "GST" {
+		t.Errorf("Country returned code %s and tax name %s, expected CA and GST", country.Code, country.TaxName)
+	}
+
+	expectedTax := decimal.NewFromFloat(0.05)
+	if country.Tax == nil || !country.Tax.Equals(expectedTax) {
+		t.Errorf("Country.Tax returned %+v, expected %+v", country.Tax, expectedTax)
+	}
+
+	if len(country.Provinces) == 0 || country.Provinces[0].Code != "AB" {
+		t.Errorf("Country.Provinces returned %+v", country.Provinces)
+	}
+}
+
+func TestCountryList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/countries.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("countries.json")))
+
+	countries, err := client.Country.List(context.Background(), nil)
+	if err != nil {
+		t.Errorf("Country.List returned error: %v", err)
+	}
+
+	if len(countries) != 1 {
+		t.Fatalf("Country.List got %v countries, expected: 1", len(countries))
+	}
+
+	countryTests(t, countries[0])
+}
+
+func TestCountryCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/countries/count.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"count": 3}`))
+
+	cnt, err := client.Country.Count(context.Background(), nil)
+	if err != nil {
+		t.Errorf("Country.Count returned error: %v", err)
+	}
+
+	expected := 3
+	if cnt != expected {
+		t.Errorf("Country.Count returned %d, expected %d", cnt, expected)
+	}
+}
+
+func TestCountryGet(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/countries/879921427.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("country.json")))
+
+	country, err := client.Country.Get(context.Background(), 879921427, nil)
+	if err != nil {
+		t.Errorf("Country.Get returned error: %v", err)
+	}
+
+	countryTests(t, *country)
+}
+
+func TestCountryCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var body map[string]interface{}
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/countries.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			return httpmock.NewBytesResponse(201, loadFixture("country.json")), nil
+		})
+
+	tax := decimal.NewFromFloat(0.05)
+	country, err := client.Country.Create(context.Background(), Country{Code: "CA", Tax: &tax})
+	if err != nil {
+		t.Errorf("Country.Create returned error: %v", err)
+	}
+
+	expectedBody := map[string]interface{}{
+		"country": map[string]interface{}{"code": "CA", "tax": "0.05"},
+	}
+	if !reflect.DeepEqual(body, expectedBody) {
+		t.Errorf("Country.Create sent %+v, expected %+v", body, expectedBody)
+	}
+
+	countryTests(t, *country)
+}
+
+func TestCountryUpdate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("PUT", fmt.Sprintf("https://fooshop.myshopify.com/%s/countries/879921427.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("country.json")))
+
+	tax := decimal.NewFromFloat(0.05)
+	country, err := client.Country.Update(context.Background(), Country{Id: 879921427, Tax: &tax})
+	if err != nil {
+		t.Errorf("Country.Update returned error: %v", err)
+	}
+
+	countryTests(t, *country)
+}
+
+func TestCountryDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("https://fooshop.myshopify.com/%s/countries/879921427.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, "{}"))
+
+	err := client.Country.Delete(context.Background(), 879921427)
+	if err != nil {
+		t.Errorf("Country.Delete returned error: %v", err)
+	}
+}
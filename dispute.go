@@ -0,0 +1,201 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const disputesBasePath = "shopify_payments/disputes"
+
+// DisputeService is an interface for interfacing with the dispute endpoints
+// of the Shopify Payments API.
+// See: https://shopify.dev/docs/api/admin-rest/latest/resources/dispute
+type DisputeService interface {
+	List(context.Context, interface{}) ([]Dispute, error)
+	ListAll(context.Context, interface{}) ([]Dispute, error)
+	ListWithPagination(context.Context, interface{}) ([]Dispute, *Pagination, error)
+	Get(context.Context, uint64, interface{}) (*Dispute, error)
+	GetEvidence(context.Context, uint64) (*DisputeEvidence, error)
+}
+
+// DisputeServiceOp handles communication with the dispute related methods of
+// the Shopify Payments API.
+type DisputeServiceOp struct {
+	client *Client
+}
+
+// A struct for all available dispute list options
+type DisputeListOptions struct {
+	PageInfo    string        `url:"page_info,omitempty"`
+	Limit       int           `url:"limit,omitempty"`
+	LastId      uint64        `url:"last_id,omitempty"`
+	SinceId     uint64        `url:"since_id,omitempty"`
+	Status      DisputeStatus `url:"status,omitempty"`
+	InitiatedAt *OnlyDate     `url:"initiated_at,omitempty"`
+}
+
+// Dispute represents a Shopify Payments dispute, a chargeback or inquiry
+// opened by a customer's bank
+type Dispute struct {
+	Id                uint64           `json:"id,omitempty"`
+	OrderId           uint64           `json:"order_id,omitempty"`
+	Type              DisputeType      `json:"type,omitempty"`
+	Amount            *decimal.Decimal `json:"amount,omitempty"`
+	Currency          string           `json:"currency,omitempty"`
+	Reason            string           `json:"reason,omitempty"`
+	NetworkReasonCode string           `json:"network_reason_code,omitempty"`
+	Status            DisputeStatus    `json:"status,omitempty"`
+	EvidenceDueBy     *time.Time       `json:"evidence_due_by,omitempty"`
+	EvidenceSentOn    *time.Time       `json:"evidence_sent_on,omitempty"`
+	FinalizedOn       *time.Time       `json:"finalized_on,omitempty"`
+	InitiatedAt       *time.Time       `json:"initiated_at,omitempty"`
+}
+
+type DisputeType string
+
+const (
+	DisputeTypeChargeback DisputeType = "chargeback"
+	DisputeTypeInquiry    DisputeType = "inquiry"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusNeedsResponse  DisputeStatus = "needs_response"
+	DisputeStatusUnderReview    DisputeStatus = "under_review"
+	DisputeStatusChargeRefunded DisputeStatus = "charge_refunded"
+	DisputeStatusAccepted       DisputeStatus = "accepted"
+	DisputeStatusWon            DisputeStatus = "won"
+	DisputeStatusLost           DisputeStatus = "lost"
+)
+
+// DisputeEvidence is the evidence submitted, or to be submitted, for a
+// dispute
+type DisputeEvidence struct {
+	Id                           uint64                       `json:"id,omitempty"`
+	PaymentsDisputeId            uint64                       `json:"payments_dispute_id,omitempty"`
+	AccessActivityLog            string                       `json:"access_activity_log,omitempty"`
+	CancellationPolicyDisclosure string                       `json:"cancellation_policy_disclosure,omitempty"`
+	CancellationRebuttal         string                       `json:"cancellation_rebuttal,omitempty"`
+	RefundPolicyDisclosure       string                       `json:"refund_policy_disclosure,omitempty"`
+	RefundRefusalExplanation     string                       `json:"refund_refusal_explanation,omitempty"`
+	UncategorizedText            string                       `json:"uncategorized_text,omitempty"`
+	CustomerEmailAddress         string                       `json:"customer_email_address,omitempty"`
+	CustomerFirstName            string                       `json:"customer_first_name,omitempty"`
+	CustomerLastName             string                       `json:"customer_last_name,omitempty"`
+	ProductDescription           *DisputeProductDescription   `json:"product_description,omitempty"`
+	BillingAddress               *Address                     `json:"billing_address,omitempty"`
+	ShippingAddress              *Address                     `json:"shipping_address,omitempty"`
+	Fulfillments                 []DisputeEvidenceFulfillment `json:"fulfillments,omitempty"`
+	DisputeFileUploads           []DisputeFileUpload          `json:"dispute_file_uploads,omitempty"`
+	CreatedAt                    *time.Time                   `json:"created_at,omitempty"`
+	UpdatedAt                    *time.Time                   `json:"updated_at,omitempty"`
+	SubmittedByMerchantOn        *time.Time                   `json:"submitted_by_merchant_on,omitempty"`
+}
+
+// DisputeProductDescription describes the disputed product
+type DisputeProductDescription struct {
+	ProductId   uint64           `json:"product_id,omitempty"`
+	Title       string           `json:"title,omitempty"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	Quantity    int              `json:"quantity,omitempty"`
+	Sku         string           `json:"sku,omitempty"`
+	Description string           `json:"description,omitempty"`
+}
+
+// DisputeEvidenceFulfillment is the shipping evidence of a dispute
+type DisputeEvidenceFulfillment struct {
+	ShippingCarrier        string    `json:"shipping_carrier,omitempty"`
+	ShippingTrackingNumber string    `json:"shipping_tracking_number,omitempty"`
+	ShippingDate           *OnlyDate `json:"shipping_date,omitempty"`
+}
+
+// DisputeFileUpload is a file uploaded as evidence of a dispute
+type DisputeFileUpload struct {
+	Id                  uint64 `json:"id,omitempty"`
+	DisputeEvidenceType string `json:"dispute_evidence_type,omitempty"`
+	FileSize            int64  `json:"file_size,omitempty"`
+	FileType            string `json:"file_type,omitempty"`
+	OriginalFileName    string `json:"original_file_name,omitempty"`
+	Url                 string `json:"url,omitempty"`
+}
+
+// Represents the result from the disputes/X.json endpoint
+type DisputeResource struct {
+	Dispute *Dispute `json:"dispute"`
+}
+
+// Represents the result from the disputes.json endpoint
+type DisputesResource struct {
+	Disputes []Dispute `json:"disputes"`
+}
+
+// Represents the result from the disputes/X/dispute_evidences.json endpoint
+type DisputeEvidenceResource struct {
+	DisputeEvidence *DisputeEvidence `json:"dispute_evidence"`
+}
+
+// List disputes
+func (s *DisputeServiceOp) List(ctx context.Context, options interface{}) ([]Dispute, error) {
+	disputes, _, err := s.ListWithPagination(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// ListAll Lists all disputes, iterating over pages
+func (s *DisputeServiceOp) ListAll(ctx context.Context, options interface{}) ([]Dispute, error) {
+	collector := []Dispute{}
+
+	for {
+		entities, pagination, err := s.ListWithPagination(ctx, options)
+
+		if err != nil {
+			return collector, err
+		}
+
+		collector = append(collector, entities...)
+
+		if pagination.NextPageOptions == nil {
+			break
+		}
+
+		options = pagination.NextPageOptions
+	}
+
+	return collector, nil
+}
+
+// ListWithPagination lists disputes and returns pagination to retrieve
+// next/previous results.
+func (s *DisputeServiceOp) ListWithPagination(ctx context.Context, options interface{}) ([]Dispute, *Pagination, error) {
+	path := fmt.Sprintf("%s.json", disputesBasePath)
+	resource := new(DisputesResource)
+
+	pagination, err := s.client.ListWithPagination(ctx, path, resource, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource.Disputes, pagination, nil
+}
+
+// Get individual dispute
+func (s *DisputeServiceOp) Get(ctx context.Context, id uint64, options interface{}) (*Dispute, error) {
+	path := fmt.Sprintf("%s/%d.json", disputesBasePath, id)
+	resource := new(DisputeResource)
+	err := s.client.Get(ctx, path, resource, options)
+	return resource.Dispute, err
+}
+
+// GetEvidence retrieves the evidence of a dispute
+func (s *DisputeServiceOp) GetEvidence(ctx context.Context, id uint64) (*DisputeEvidence, error) {
+	path := fmt.Sprintf("%s/%d/dispute_evidences.json", disputesBasePath, id)
+	resource := new(DisputeEvidenceResource)
+	err := s.client.Get(ctx, path, resource, nil)
+	return resource.DisputeEvidence, err
+}
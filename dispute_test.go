@@ -0,0 +1,112 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+)
+
+func disputeTests(t *testing.T, dispute Dispute) {
+	expectedId := uint64(598735659)
+	if dispute.Id != expectedId {
+		t.Errorf("Dispute.Id returned %+v, expected %+v", dispute.Id, expectedId)
+	}
+
+	expectedOrderId := uint64(625362839)
+	if dispute.OrderId != expectedOrderId {
+		t.Errorf("Dispute.OrderId returned %+v, expected %+v", dispute.OrderId, expectedOrderId)
+	}
+
+	if dispute.Type != DisputeTypeChargeback || dispute.Status != DisputeStatusNeedsResponse {
+		t.Errorf("Dispute returned type %s and status %s", dispute.Type, dispute.Status)
+	}
+
+	expectedAmount := decimal.NewFromFloat(11.5)
+	if dispute.Amount == nil || !dispute.Amount.Equals(expectedAmount) {
+		t.Errorf("Dispute.Amount returned %+v, expected %+v", dispute.Amount, expectedAmount)
+	}
+
+	expectedDueBy := time.Date(2024, time.January, 13, 0, 0, 0, 0, time.UTC)
+	if dispute.EvidenceDueBy == nil || !expectedDueBy.Equal(*dispute.EvidenceDueBy) {
+		t.Errorf("Dispute.EvidenceDueBy returned %+v, expected %+v", dispute.EvidenceDueBy, expectedDueBy)
+	}
+
+	if dispute.FinalizedOn != nil {
+		t.Errorf("Dispute.FinalizedOn returned %+v, expected nil", dispute.FinalizedOn)
+	}
+}
+
+func TestDisputeList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/disputes.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"disputes": []}`))
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/disputes.json", client.pathPrefix),
+		map[string]string{"status": "needs_response"},
+		httpmock.NewBytesResponder(200, loadFixture("disputes.json")))
+
+	disputes, err := client.Dispute.List(context.Background(), DisputeListOptions{Status: DisputeStatusNeedsResponse})
+	if err != nil {
+		t.Errorf("Dispute.List returned error: %v", err)
+	}
+
+	if len(disputes) != 2 {
+		t.Fatalf("Dispute.List got %v disputes, expected: 2", len(disputes))
+	}
+
+	disputeTests(t, disputes[0])
+}
+
+func TestDisputeGet(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/disputes/598735659.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("dispute.json")))
+
+	dispute, err := client.Dispute.Get(context.Background(), 598735659, nil)
+	if err != nil {
+		t.Errorf("Dispute.Get returned error: %v", err)
+	}
+
+	disputeTests(t, *dispute)
+}
+
+func TestDisputeGetEvidence(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/disputes/598735659/dispute_evidences.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("dispute_evidence.json")))
+
+	evidence, err := client.Dispute.GetEvidence(context.Background(), 598735659)
+	if err != nil {
+		t.Fatalf("Dispute.GetEvidence returned error: %v", err)
+	}
+
+	expectedDisputeId := uint64(598735659)
+	if evidence.PaymentsDisputeId != expectedDisputeId {
+		t.Errorf("DisputeEvidence.PaymentsDisputeId returned %+v, expected %+v", evidence.PaymentsDisputeId, expectedDisputeId)
+	}
+
+	if evidence.ProductDescription == nil || evidence.ProductDescription.ProductId != 632910392 {
+		t.Errorf("DisputeEvidence.ProductDescription returned %+v", evidence.ProductDescription)
+	}
+
+	if evidence.ShippingAddress == nil || evidence.ShippingAddress.City != "Ottawa" {
+		t.Errorf("DisputeEvidence.ShippingAddress returned %+v", evidence.ShippingAddress)
+	}
+
+	if len(evidence.Fulfillments) != 1 || evidence.Fulfillments[0].ShippingTrackingNumber != "1Z2345" {
+		t.Errorf("DisputeEvidence.Fulfillments returned %+v", evidence.Fulfillments)
+	}
+
+	if len(evidence.DisputeFileUploads) != 1 || evidence.DisputeFileUploads[0].OriginalFileName != "receipt.pdf" {
+		t.Errorf("DisputeEvidence.DisputeFileUploads returned %+v", evidence.DisputeFileUploads)
+	}
+}
@@ -0,0 +1,145 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const eventsBasePath = "events"
+
+// EventService is an interface for interfacing with the event endpoints of
+// the Shopify API.
+// See: https://shopify.dev/docs/api/admin-rest/latest/resources/event
+type EventService interface {
+	List(context.Context, interface{}) ([]Event, error)
+	ListAll(context.Context, interface{}) ([]Event, error)
+	ListWithPagination(context.Context, interface{}) ([]Event, *Pagination, error)
+	ListByResource(context.Context, string, uint64, interface{}) ([]Event, error)
+	ListByResourceWithPagination(context.Context, string, uint64, interface{}) ([]Event, *Pagination, error)
+	Count(context.Context, interface{}) (int, error)
+	Get(context.Context, uint64, interface{}) (*Event, error)
+}
+
+// EventServiceOp handles communication with the event related methods of
+// the Shopify API.
+type EventServiceOp struct {
+	client *Client
+}
+
+// Event is an action taken on a resource of the shop, e.g. a product being
+// deleted.
+type Event struct {
+	Id          uint64          `json:"id,omitempty"`
+	SubjectId   uint64          `json:"subject_id,omitempty"`
+	SubjectType string          `json:"subject_type,omitempty"`
+	Verb        string          `json:"verb,omitempty"`
+	Arguments   json.RawMessage `json:"arguments,omitempty"`
+	Body        *string         `json:"body,omitempty"`
+	Message     string          `json:"message,omitempty"`
+	Author      string          `json:"author,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Path        string          `json:"path,omitempty"`
+	CreatedAt   *time.Time      `json:"created_at,omitempty"`
+}
+
+// EventListOptions are the options of listing and counting events
+type EventListOptions struct {
+	ListOptions
+
+	// Filter restricts events to subject types, e.g. "Product,Order"
+	Filter string `url:"filter,omitempty"`
+
+	// Verb restricts events to an action, e.g. "destroy"
+	Verb string `url:"verb,omitempty"`
+}
+
+// EventResource represents the result from the events/X.json endpoint
+type EventResource struct {
+	Event *Event `json:"event"`
+}
+
+// EventsResource represents the result from the events.json endpoint
+type EventsResource struct {
+	Events []Event `json:"events"`
+}
+
+// List events
+func (s *EventServiceOp) List(ctx context.Context, options interface{}) ([]Event, error) {
+	events, _, err := s.ListWithPagination(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListAll lists all events, iterating over pages
+func (s *EventServiceOp) ListAll(ctx context.Context, options interface{}) ([]Event, error) {
+	collector := []Event{}
+
+	for {
+		entities, pagination, err := s.ListWithPagination(ctx, options)
+
+		if err != nil {
+			return collector, err
+		}
+
+		collector = append(collector, entities...)
+
+		if pagination.NextPageOptions == nil {
+			break
+		}
+
+		options = pagination.NextPageOptions
+	}
+
+	return collector, nil
+}
+
+// ListWithPagination lists events and returns pagination to retrieve
+// next/previous results.
+func (s *EventServiceOp) ListWithPagination(ctx context.Context, options interface{}) ([]Event, *Pagination, error) {
+	return s.list(ctx, fmt.Sprintf("%s.json", eventsBasePath), options)
+}
+
+// ListByResource lists the events of a resource, e.g. "products" or
+// "orders", and resourceId.
+func (s *EventServiceOp) ListByResource(ctx context.Context, resource string, resourceId uint64, options interface{}) ([]Event, error) {
+	events, _, err := s.ListByResourceWithPagination(ctx, resource, resourceId, options)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListByResourceWithPagination lists the events of a resource and returns
+// pagination to retrieve next/previous results.
+func (s *EventServiceOp) ListByResourceWithPagination(ctx context.Context, resource string, resourceId uint64, options interface{}) ([]Event, *Pagination, error) {
+	return s.list(ctx, fmt.Sprintf("%s.json", EventPathPrefix(resource, resourceId)), options)
+}
+
+func (s *EventServiceOp) list(ctx context.Context, path string, options interface{}) ([]Event, *Pagination, error) {
+	resource := new(EventsResource)
+
+	pagination, err := s.client.ListWithPagination(ctx, path, resource, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource.Events, pagination, nil
+}
+
+// Count events
+func (s *EventServiceOp) Count(ctx context.Context, options interface{}) (int, error) {
+	path := fmt.Sprintf("%s/count.json", eventsBasePath)
+	return s.client.Count(ctx, path, options)
+}
+
+// Get individual event
+func (s *EventServiceOp) Get(ctx context.Context, eventId uint64, options interface{}) (*Event, error) {
+	path := fmt.Sprintf("%s/%d.json", eventsBasePath, eventId)
+	resource := new(EventResource)
+	err := s.client.Get(ctx, path, resource, options)
+	return resource.Event, err
+}
@@ -0,0 +1,166 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func eventTests(t *testing.T, event Event) {
+	expectedId := uint64(164748010)
+	if event.Id != expectedId {
+		t.Errorf("Event.Id returned %+v, expected %+v", event.Id, expectedId)
+	}
+
+	expectedSubjectId := uint64(632910392)
+	if event.SubjectId != expectedSubjectId {
+		t.Errorf("Event.SubjectId returned %+v, expected %+v", event.SubjectId, expectedSubjectId)
+	}
+
+	if event.SubjectType != "Product" || event.Verb != "destroy" {
+		t.Errorf("Event returned subject type %s and verb %s, expected Product and destroy", event.SubjectType, event.Verb)
+	}
+
+	expectedCreatedAt := time.Date(2008, time.January, 10, 13, 0, 0, 0, time.UTC)
+	if event.CreatedAt == nil || !expectedCreatedAt.Equal(*event.CreatedAt) {
+		t.Errorf("Event.CreatedAt returned %+v, expected %+v", event.CreatedAt, expectedCreatedAt)
+	}
+
+	if event.Body != nil {
+		t.Errorf("Event.Body returned %+v, expected nil", event.Body)
+	}
+}
+
+func TestEventList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/events.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"events": []}`))
+
+	params := map[string]string{
+		"filter":         "Product,Order",
+		"verb":           "destroy",
+		"created_at_min": "2008-01-01T00:00:00Z",
+		"since_id":       "100",
+	}
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/events.json", client.pathPrefix),
+		params, httpmock.NewBytesResponder(200, loadFixture("events.json")))
+
+	sinceId := uint64(100)
+	events, err := client.Event.List(context.Background(), EventListOptions{
+		ListOptions: ListOptions{
+			SinceId:      &sinceId,
+			CreatedAtMin: time.Date(2008, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Filter: "Product,Order",
+		Verb:   "destroy",
+	})
+	if err != nil {
+		t.Errorf("Event.List returned error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Event.List got %v events, expected: 2", len(events))
+	}
+
+	eventTests(t, events[0])
+}
+
+func TestEventListAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/events.json", client.pathPrefix)
+
+	httpmock.RegisterResponder("GET", listURL,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewBytesResponse(200, loadFixture("events.json"))
+			resp.Header.Set("Link", fmt.Sprintf(`<%s?page_info=pg2&limit=2>; rel="next"`, listURL))
+			return resp, nil
+		})
+	httpmock.RegisterResponderWithQuery("GET", listURL, map[string]string{"page_info": "pg2", "limit": "2"},
+		httpmock.NewStringResponder(200, `{"events": [{"id": 1}]}`))
+
+	events, err := client.Event.ListAll(context.Background(), nil)
+	if err != nil {
+		t.Errorf("Event.ListAll returned error: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Errorf("Event.ListAll got %v events, expected: 3", len(events))
+	}
+}
+
+func TestEventListByResource(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/products/632910392/events.json", client.pathPrefix)
+	httpmock.RegisterResponder("GET", listURL,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewBytesResponse(200, loadFixture("events.json"))
+			resp.Header.Set("Link", `<http://valid.url?page_info=pageInfoCode&limit=2>; rel="next"`)
+			return resp, nil
+		})
+
+	events, pagination, err := client.Event.ListByResourceWithPagination(context.Background(), "products", 632910392, nil)
+	if err != nil {
+		t.Fatalf("Event.ListByResourceWithPagination returned error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Errorf("Event.ListByResourceWithPagination got %v events, expected: 2", len(events))
+	}
+
+	expectedPageInfo := &ListOptions{PageInfo: "pageInfoCode", Limit: 2}
+	if !reflect.DeepEqual(pagination.NextPageOptions, expectedPageInfo) {
+		t.Errorf("Event.ListByResourceWithPagination next page returned %+v, expected %+v", pagination.NextPageOptions, expectedPageInfo)
+	}
+
+	events, err = client.Event.ListByResource(context.Background(), "products", 632910392, nil)
+	if err != nil {
+		t.Errorf("Event.ListByResource returned error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Event.ListByResource got %v events, expected: 2", len(events))
+	}
+}
+
+func TestEventCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/events/count.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"count": 3}`))
+
+	cnt, err := client.Event.Count(context.Background(), nil)
+	if err != nil {
+		t.Errorf("Event.Count returned error: %v", err)
+	}
+
+	expected := 3
+	if cnt != expected {
+		t.Errorf("Event.Count returned %d, expected %d", cnt, expected)
+	}
+}
+
+func TestEventGet(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/events/164748010.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("event.json")))
+
+	event, err := client.Event.Get(context.Background(), 164748010, nil)
+	if err != nil {
+		t.Errorf("Event.Get returned error: %v", err)
+	}
+
+	eventTests(t, *event)
+}
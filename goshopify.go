@@ -17,6 +17,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -55,11 +56,21 @@ type RateLimitInfo struct {
 }
 
 // Client manages communication with the Shopify API.
+//
+// A Client is safe for concurrent use by multiple goroutines. Per request
+// state is kept local to the request, the rate limit snapshot of the last
+// response is available through RateLimitSnapshot.
 type Client struct {
 	// HTTP client used to communicate with the Shopify API.
 	Client *http.Client
 	log    LeveledLoggerInterface
 
+	// RateLimits is the rate limit information of the last response.
+	//
+	// Deprecated: RateLimits is written by every response and reading it is
+	// not safe while other goroutines use the client. Use RateLimitSnapshot.
+	RateLimits RateLimitInfo
+
 	// App settings
 	app App
 
@@ -71,20 +82,39 @@ type Client struct {
 	// URL Prefix, defaults to "admin" see WithVersion
 	pathPrefix string
 
-	// version you're currently using of the api, defaults to "stable"
-	apiVersion string
-
 	// A permanent access token
 	token string
 
-	// max number of retries, defaults to 0 for no retries see WithRetry option
-	retries  int
-	attempts int
+	// supplies the access token of every request instead of token, see
+	// WithTokenSource
+	tokenSource TokenSource
 
-	RateLimits RateLimitInfo
+	// retry behaviour, defaults to no retries see WithRetry and
+	// WithRetryPolicy options
+	retryPolicy RetryPolicy
+
+	// mu guards the fields below which are updated from responses
+	mu sync.RWMutex
+
+	// version you're currently using of the api, defaults to "stable"
+	apiVersion string
+
+	rateLimits RateLimitInfo
+
+	// optional client side rate limiter, see WithRateLimiter
+	rateLimiter RateLimiter
 
 	additionalHeaders map[string]string
 
+	// structured logging of every call, see WithStructuredLogger
+	structuredLog StructuredLogger
+
+	// masks secrets and personal data before logging, see WithRedactor
+	redactor *Redactor
+
+	// wraps every round trip, see WithMiddleware
+	middleware []Middleware
+
 	// Services used for communicating with the API
 	Product                    ProductService
 	CustomCollection           CustomCollectionService
@@ -100,6 +130,9 @@ type Client struct {
 	Variant                    VariantService
 	Image                      ImageService
 	Transaction                TransactionService
+	Refund                     RefundService
+	TenderTransaction          TenderTransactionService
+	Event                      EventService
 	Theme                      ThemeService
 	Asset                      AssetService
 	ScriptTag                  ScriptTagService
@@ -118,6 +151,8 @@ type Client struct {
 	PriceRule                  PriceRuleService
 	InventoryItem              InventoryItemService
 	ShippingZone               ShippingZoneService
+	Country                    CountryService
+	Province                   ProvinceService
 	ProductListing             ProductListingService
 	InventoryLevel             InventoryLevelService
 	AccessScopes               AccessScopesService
@@ -127,10 +162,13 @@ type Client struct {
 	GiftCard                   GiftCardService
 	FulfillmentOrder           FulfillmentOrderService
 	GraphQL                    GraphQLService
+	BulkOperation              BulkOperationService
 	AssignedFulfillmentOrder   AssignedFulfillmentOrderService
 	FulfillmentEvent           FulfillmentEventService
 	FulfillmentRequest         FulfillmentRequestService
 	PaymentsTransactions       PaymentsTransactionsService
+	Dispute                    DisputeService
+	Balance                    BalanceService
 	OrderRisk                  OrderRiskService
 	ApiPermissions             ApiPermissionsService
 	Article                    ArticlesService
@@ -142,6 +180,9 @@ type ResponseError struct {
 	Status  int
 	Message string
 	Errors  []string
+
+	// GraphQLErrors holds the typed errors of a GraphQL response
+	GraphQLErrors []GraphQLError
 }
 
 // GetStatus returns http  response status
@@ -159,6 +200,11 @@ func (e ResponseError) GetErrors() []string {
 	return e.Errors
 }
 
+// GetGraphQLErrors returns the typed errors of a GraphQL response
+func (e ResponseError) GetGraphQLErrors() []GraphQLError {
+	return e.GraphQLErrors
+}
+
 func (e ResponseError) Error() string {
 	if e.Message != "" {
 		return e.Message
@@ -193,6 +239,14 @@ type RateLimitError struct {
 	RetryAfter int
 }
 
+// An error specific to a 401 Unauthorized response, returned when the access
+// token is invalid, was revoked or, for online tokens, has expired. Embeds the
+// ResponseError to allow consumers to handle it the same was a normal
+// ResponseError.
+type UnauthorizedError struct {
+	ResponseError
+}
+
 // Creates an API request. A relative URL can be provided in urlStr, which will
 // be resolved to the BaseURL of the Client. Relative URLS should always be
 // specified without a preceding slash. If specified, the value pointed to by
@@ -242,7 +296,13 @@ func (c *Client) NewRequest(ctx context.Context, method, relPath string, body, o
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("User-Agent", UserAgent)
 
-	if c.token != "" {
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("X-Shopify-Access-Token", token.Token)
+	} else if c.token != "" {
 		req.Header.Add("X-Shopify-Access-Token", c.token)
 	} else if c.app.Password != "" {
 		req.SetBasicAuth(c.app.ApiKey, c.app.Password)
@@ -291,6 +351,7 @@ func NewClient(app App, shopName, token string, opts ...Option) (*Client, error)
 			Timeout: time.Second * defaultHttpTimeout,
 		},
 		log:        &LeveledLogger{},
+		redactor:   DefaultRedactor(),
 		app:        app,
 		baseURL:    baseURL,
 		token:      token,
@@ -312,6 +373,9 @@ func NewClient(app App, shopName, token string, opts ...Option) (*Client, error)
 	c.Variant = &VariantServiceOp{client: c}
 	c.Image = &ImageServiceOp{client: c}
 	c.Transaction = &TransactionServiceOp{client: c}
+	c.Refund = &RefundServiceOp{client: c}
+	c.TenderTransaction = &TenderTransactionServiceOp{client: c}
+	c.Event = &EventServiceOp{client: c}
 	c.Theme = &ThemeServiceOp{client: c}
 	c.Asset = &AssetServiceOp{client: c}
 	c.ScriptTag = &ScriptTagServiceOp{client: c}
@@ -330,6 +394,8 @@ func NewClient(app App, shopName, token string, opts ...Option) (*Client, error)
 	c.PriceRule = &PriceRuleServiceOp{client: c}
 	c.InventoryItem = &InventoryItemServiceOp{client: c}
 	c.ShippingZone = &ShippingZoneServiceOp{client: c}
+	c.Country = &CountryServiceOp{client: c}
+	c.Province = &ProvinceServiceOp{client: c}
 	c.ProductListing = &ProductListingServiceOp{client: c}
 	c.InventoryLevel = &InventoryLevelServiceOp{client: c}
 	c.AccessScopes = &AccessScopesServiceOp{client: c}
@@ -343,9 +409,12 @@ func NewClient(app App, shopName, token string, opts ...Option) (*Client, error)
 	c.FulfillmentEvent = &FulfillmentEventServiceOp{client: c}
 	c.FulfillmentRequest = &FulfillmentRequestServiceOp{client: c}
 	c.PaymentsTransactions = &PaymentsTransactionsServiceOp{client: c}
+	c.Dispute = &DisputeServiceOp{client: c}
+	c.Balance = &BalanceServiceOp{client: c}
 	c.OrderRisk = &OrderRiskServiceOp{client: c}
 	c.ApiPermissions = &ApiPermissionsServiceOp{client: c}
 	c.Article = &ArticlesServiceOp{client: c}
+	c.BulkOperation = &BulkOperationServiceOp{client: c}
 
 	// apply any options
 	for _, opt := range opts {
@@ -367,12 +436,36 @@ func (c *Client) Do(req *http.Request, v interface{}) error {
 	return nil
 }
 
+// refreshToken replaces the access token of req, rejected by Shopify, with a
+// new one of the client's token source. It returns false if the source
+// cannot drop the rejected token.
+func (c *Client) refreshToken(req *http.Request) (bool, error) {
+	invalidator, ok := c.tokenSource.(tokenInvalidator)
+	if !ok {
+		return false, nil
+	}
+
+	rejected := req.Header.Get("X-Shopify-Access-Token")
+	invalidator.Invalidate(rejected)
+
+	token, err := c.tokenSource.Token(req.Context())
+	if err != nil {
+		return false, err
+	}
+	if token.Token == rejected {
+		return false, nil
+	}
+
+	c.log.Debugf("access token rejected, retrying with a new token")
+	req.Header.Set("X-Shopify-Access-Token", token.Token)
+	return true, nil
+}
+
 // doGetHeaders executes a request, decoding the response into `v` and also returns any response headers.
 func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, error) {
 	var resp *http.Response
 	var err error
-	retries := c.retries
-	c.attempts = 0
+	policy := c.retryPolicy
 	c.logRequest(req)
 
 	// copy request body so it can be re-used
@@ -385,13 +478,42 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 		}
 	}
 
-	for {
-		c.attempts++
+	// a request rejected with a 401 is retried once with a new token
+	tokenRefreshed := false
+
+	for attempt := 1; ; attempt++ {
+		if c.rateLimiter != nil && !isGraphQLRequest(req) {
+			if err := c.rateLimiter.WaitREST(req.Context(), c.shopDomain()); err != nil {
+				return nil, err
+			}
+		}
+
 		req.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-		resp, err = c.Client.Do(req)
+		start := time.Now()
+		resp, err = c.roundTrip(req)
 		c.logResponse(resp)
+		c.logCall(req, body, resp, err, time.Since(start), attempt)
 		if err != nil {
-			return nil, err // http client errors, not api responses
+			// http client errors, not api responses
+			if !policy.retryOnError(req, err, attempt) {
+				return nil, err
+			}
+
+			delay := policy.delay(nil, attempt)
+			c.log.Debugf("request failed with %v, retrying in %s", err, delay)
+			info := RetryInfo{Request: req, Attempt: attempt, Err: err, Delay: delay}
+			if waitErr := policy.waitRetry(req.Context(), info); waitErr != nil {
+				return nil, waitErr
+			}
+			continue
+		}
+
+		if c.rateLimiter != nil {
+			if s := strings.Split(resp.Header.Get("X-Shopify-Shop-Api-Call-Limit"), "/"); len(s) == 2 {
+				requestCount, _ := strconv.Atoi(s[0])
+				bucketSize, _ := strconv.Atoi(s[1])
+				c.rateLimiter.ObserveREST(c.shopDomain(), requestCount, bucketSize)
+			}
 		}
 
 		respErr := CheckResponseError(resp)
@@ -402,42 +524,44 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 		// retry scenario, close resp and any continue will retry
 		resp.Body.Close()
 
-		if retries <= 1 {
-			return nil, respErr
+		if _, unauthorized := respErr.(UnauthorizedError); unauthorized && !tokenRefreshed {
+			if refreshed, refreshErr := c.refreshToken(req); refreshErr != nil {
+				return nil, refreshErr
+			} else if refreshed {
+				tokenRefreshed = true
+				attempt--
+				continue
+			}
 		}
 
-		if rateLimitErr, isRetryErr := respErr.(RateLimitError); isRetryErr {
-			// back off and retry
-
-			wait := time.Duration(rateLimitErr.RetryAfter) * time.Second
-			c.log.Debugf("rate limited waiting %s", wait.String())
-			time.Sleep(wait)
-			retries--
-			continue
+		if !policy.retryOnStatus(req, resp.StatusCode, attempt) {
+			// no retry attempts, just return the err
+			return nil, respErr
 		}
 
-		var doRetry bool
-		switch resp.StatusCode {
-		case http.StatusServiceUnavailable:
-			c.log.Debugf("service unavailable, retrying")
-			doRetry = true
-			retries--
+		delay := policy.delay(resp, attempt)
+		if _, isRateLimitErr := respErr.(RateLimitError); isRateLimitErr {
+			c.log.Debugf("rate limited waiting %s", delay.String())
+		} else {
+			c.log.Debugf("received status %d, retrying in %s", resp.StatusCode, delay.String())
 		}
 
-		if doRetry {
-			continue
+		info := RetryInfo{Request: req, Attempt: attempt, StatusCode: resp.StatusCode, Err: respErr, Delay: delay}
+		if waitErr := policy.waitRetry(req.Context(), info); waitErr != nil {
+			return nil, waitErr
 		}
-
-		// no retry attempts, just return the err
-		return nil, respErr
 	}
 
 	defer resp.Body.Close()
 
-	if c.apiVersion == defaultApiVersion && resp.Header.Get("X-Shopify-API-Version") != "" {
-		// if using stable on first request set the api version
-		c.apiVersion = resp.Header.Get("X-Shopify-API-Version")
-		c.log.Infof("api version not set, now using %s", c.apiVersion)
+	if version := resp.Header.Get("X-Shopify-API-Version"); version != "" {
+		c.mu.Lock()
+		if c.apiVersion == defaultApiVersion {
+			// if using stable on first request set the api version
+			c.apiVersion = version
+			c.log.Infof("api version not set, now using %s", version)
+		}
+		c.mu.Unlock()
 	}
 
 	if v != nil {
@@ -448,16 +572,74 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 		}
 	}
 
+	var requestCount, bucketSize int
 	if s := strings.Split(resp.Header.Get("X-Shopify-Shop-Api-Call-Limit"), "/"); len(s) == 2 {
-		c.RateLimits.RequestCount, _ = strconv.Atoi(s[0])
-		c.RateLimits.BucketSize, _ = strconv.Atoi(s[1])
+		requestCount, _ = strconv.Atoi(s[0])
+		bucketSize, _ = strconv.Atoi(s[1])
 	}
+	retryAfterSeconds, _ := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
 
-	c.RateLimits.RetryAfterSeconds, _ = strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+	c.mu.Lock()
+	c.rateLimits.RequestCount = requestCount
+	c.rateLimits.BucketSize = bucketSize
+	c.rateLimits.RetryAfterSeconds = retryAfterSeconds
+	c.RateLimits = c.rateLimits
+	c.mu.Unlock()
 
 	return resp.Header, nil
 }
 
+// RateLimitSnapshot returns a snapshot of the rate limit information of the
+// last response received by the client.
+func (c *Client) RateLimitSnapshot() RateLimitInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	info := c.rateLimits
+	if info.GraphQLCost != nil {
+		cost := *info.GraphQLCost
+		if cost.ActualQueryCost != nil {
+			actual := *cost.ActualQueryCost
+			cost.ActualQueryCost = &actual
+		}
+		info.GraphQLCost = &cost
+	}
+
+	return info
+}
+
+// setGraphQLRateLimits records the cost of the last GraphQL query.
+func (c *Client) setGraphQLRateLimits(cost GraphQLCost, retryAfterSeconds float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rateLimits.GraphQLCost = &cost
+	c.rateLimits.RetryAfterSeconds = retryAfterSeconds
+	c.RateLimits = c.rateLimits
+}
+
+// ApiVersion returns the version of the API the client is using. When no
+// version was set with WithVersion it is "stable" until the first response
+// tells which version that is.
+func (c *Client) ApiVersion() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.apiVersion
+}
+
+// shopDomain returns the domain of the shop the client talks to, e.g.
+// "theshop.myshopify.com".
+func (c *Client) shopDomain() string {
+	return c.baseURL.Host
+}
+
+// isGraphQLRequest reports whether req is a call to the GraphQL endpoint,
+// which is rate limited by query cost instead of the REST bucket.
+func isGraphQLRequest(req *http.Request) bool {
+	return req.URL != nil && strings.HasSuffix(req.URL.Path, "/graphql.json")
+}
+
 func (c *Client) logRequest(req *http.Request) {
 	if req == nil {
 		return
@@ -487,11 +669,84 @@ func (c *Client) logBody(body *io.ReadCloser, format string) {
 		return
 	}
 	if len(b) > 0 {
-		c.log.Debugf(format, string(b))
+		c.log.Debugf(format, string(c.redactBody(b)))
 	}
 	*body = ioutil.NopCloser(bytes.NewBuffer(b))
 }
 
+func (c *Client) redactBody(b []byte) []byte {
+	if c.redactor == nil {
+		return b
+	}
+	return c.redactor.RedactBody(b)
+}
+
+func (c *Client) redactHeader(h http.Header) http.Header {
+	if c.redactor == nil {
+		return h
+	}
+	return c.redactor.RedactHeader(h)
+}
+
+// logCall emits a structured record for a single attempt of req. At debug
+// level the record carries the redacted headers and bodies as well.
+func (c *Client) logCall(req *http.Request, reqBody []byte, resp *http.Response, err error, duration time.Duration, attempt int) {
+	if c.structuredLog == nil {
+		return
+	}
+	ctx := req.Context()
+
+	keyvals := []interface{}{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"shop", c.shopDomain(),
+		"attempt", attempt,
+		"duration", duration,
+	}
+
+	level := LevelInfo
+	msg := "shopify request"
+	if err != nil {
+		level = LevelError
+		msg = "shopify request failed"
+		keyvals = append(keyvals, "error", err.Error())
+	} else {
+		keyvals = append(keyvals, "status", resp.StatusCode, "request_id", resp.Header.Get("X-Request-Id"))
+		switch {
+		case resp.StatusCode >= http.StatusInternalServerError:
+			level = LevelError
+		case resp.StatusCode >= http.StatusBadRequest:
+			level = LevelWarn
+		}
+	}
+
+	if c.structuredLog.Enabled(ctx, LevelDebug) {
+		keyvals = append(keyvals,
+			"request_headers", c.redactHeader(req.Header),
+			"request_body", string(c.redactBody(reqBody)))
+		if resp != nil {
+			keyvals = append(keyvals,
+				"response_headers", c.redactHeader(resp.Header),
+				"response_body", string(c.redactBody(peekBody(&resp.Body))))
+		}
+	}
+
+	if c.structuredLog.Enabled(ctx, level) {
+		c.structuredLog.Log(ctx, level, msg, keyvals...)
+	}
+}
+
+// peekBody reads body and replaces it with a reader over the same bytes.
+func peekBody(body *io.ReadCloser) []byte {
+	if body == nil || *body == nil {
+		return nil
+	}
+	b, _ := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewBuffer(b))
+	return b
+}
+
 func wrapSpecificError(r *http.Response, err ResponseError) error {
 	// see https://www.shopify.dev/concepts/about-apis/response-codes
 	if err.Status == http.StatusTooManyRequests {
@@ -502,6 +757,10 @@ func wrapSpecificError(r *http.Response, err ResponseError) error {
 		}
 	}
 
+	if err.Status == http.StatusUnauthorized {
+		return UnauthorizedError{ResponseError: err}
+	}
+
 	// if err.Status == http.StatusSeeOther {
 	// todo
 	// The response to the request can be found under a different URL in the
@@ -315,6 +315,16 @@ func TestDo(t *testing.T) {
 				Message: "Not Acceptable",
 			},
 		},
+		{
+			"foo/9",
+			httpmock.NewStringResponder(401, `{"errors":"[API] Invalid API key or access token (unrecognized login or wrong password)"}`),
+			UnauthorizedError{
+				ResponseError: ResponseError{
+					Status:  401,
+					Message: "[API] Invalid API key or access token (unrecognized login or wrong password)",
+				},
+			},
+		},
 		{
 			"foo/8",
 			httpmock.NewStringResponder(500, "<html></html>"),
@@ -463,8 +473,9 @@ func TestRetry(t *testing.T) {
 
 		err = client.Do(req, body)
 
-		if client.attempts != c.retries {
-			t.Errorf("Do(): attempts do not match retries %#v, actual %#v", client.attempts, c.retries)
+		attempts := httpmock.GetCallCountInfo()["GET "+fmt.Sprintf(urlFormat, c.relPath)]
+		if attempts != c.retries {
+			t.Errorf("Do(): attempts do not match retries %#v, actual %#v", attempts, c.retries)
 		}
 
 		if err != nil {
@@ -545,10 +556,10 @@ func TestClientDoAutoApiVersion(t *testing.T) {
 		t.Errorf("TestClientDoApiVersion(): errored %s", err)
 	}
 
-	if expected != testClient.apiVersion {
+	if expected != testClient.ApiVersion() {
 		t.Errorf(
 			"TestClientDoApiVersion(): client unable to get API Version from X-Shopify-API-Version: expected %s received %s",
-			expected, testClient.apiVersion)
+			expected, testClient.ApiVersion())
 	}
 }
 
@@ -953,8 +964,10 @@ func TestDoRateLimit(t *testing.T) {
 				if !reflect.DeepEqual(err, c.expected) {
 					t.Errorf("Do(): expected error %#v, actual %#v", c.expected, err)
 				}
+			} else if err == nil && !reflect.DeepEqual(client.RateLimitSnapshot(), c.expected) {
+				t.Errorf("%s: expected %#v, actual %#v", c.description, c.expected, client.RateLimitSnapshot())
 			} else if err == nil && !reflect.DeepEqual(client.RateLimits, c.expected) {
-				t.Errorf("%s: expected %#v, actual %#v", c.description, c.expected, client.RateLimits)
+				t.Errorf("%s: expected deprecated RateLimits %#v, actual %#v", c.description, c.expected, client.RateLimits)
 			}
 		})
 	}
@@ -1,8 +1,13 @@
 package goshopify
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"math"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -21,7 +26,7 @@ type GraphQLServiceOp struct {
 
 type graphQLResponse struct {
 	Data       interface{}        `json:"data"`
-	Errors     []graphQLError     `json:"errors"`
+	Errors     []GraphQLError     `json:"errors"`
 	Extensions *graphQLExtensions `json:"extensions"`
 }
 
@@ -43,28 +48,78 @@ type GraphQLThrottleStatus struct {
 	RestoreRate        float64 `json:"restoreRate"`
 }
 
-type graphQLError struct {
+// GraphQLError is a top level error of a GraphQL response, e.g. a syntax
+// error, an access denied or a throttled query.
+type GraphQLError struct {
 	Message    string                  `json:"message"`
-	Extensions *graphQLErrorExtensions `json:"extensions"`
-	Locations  []graphQLErrorLocation  `json:"locations"`
+	Extensions *GraphQLErrorExtensions `json:"extensions"`
+	Locations  []GraphQLErrorLocation  `json:"locations"`
+	// Path to the field that failed, made of field names and list indexes.
+	Path []interface{} `json:"path"`
 }
 
-type graphQLErrorExtensions struct {
-	Code          string
-	Documentation string
+// GraphQLErrorExtensions holds the machine readable details of a GraphQLError
+type GraphQLErrorExtensions struct {
+	Code          string `json:"code"`
+	Documentation string `json:"documentation"`
 }
 
+// GraphQLErrorLocation is the position of a GraphQLError in the query
+type GraphQLErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error codes of GraphQLError
 const (
-	graphQLErrorCodeThrottled = "THROTTLED"
+	GraphQLErrorCodeThrottled           = "THROTTLED"
+	GraphQLErrorCodeAccessDenied        = "ACCESS_DENIED"
+	GraphQLErrorCodeMaxCostExceeded     = "MAX_COST_EXCEEDED"
+	GraphQLErrorCodeInternalServerError = "INTERNAL_SERVER_ERROR"
 )
 
-type graphQLErrorLocation struct {
-	Line   int `json:"line"`
-	Column int `json:"column"`
+// Code returns the error code of the error, or an empty string
+func (e GraphQLError) Code() string {
+	if e.Extensions == nil {
+		return ""
+	}
+	return e.Extensions.Code
+}
+
+func (e GraphQLError) Error() string {
+	return e.Message
+}
+
+// GraphQLUserError is a validation error of a mutation, reported in the
+// userErrors field of its payload.
+type GraphQLUserError struct {
+	// Field is the path to the input field that caused the error
+	Field   []string `json:"field"`
+	Message string   `json:"message"`
+	Code    string   `json:"code,omitempty"`
+}
+
+// UserErrors is returned by Mutate when a mutation payload reports user
+// errors. Unlike a ResponseError the request itself succeeded, retrying
+// it with the same input fails again.
+type UserErrors []GraphQLUserError
+
+func (e UserErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ue := range e {
+		if len(ue.Field) > 0 {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", strings.Join(ue.Field, "."), ue.Message))
+			continue
+		}
+		msgs = append(msgs, ue.Message)
+	}
+	return strings.Join(msgs, ", ")
 }
 
 // Query creates a graphql query against the Shopify API
-// the "data" portion of the response is unmarshalled into resp
+// the "data" portion of the response is unmarshalled into resp.
+// Errors in the response are returned as a ResponseError with the typed
+// errors in GraphQLErrors, any partial data is still unmarshalled into resp.
 func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp interface{}) error {
 	data := struct {
 		Query     string      `json:"query"`
@@ -81,6 +136,12 @@ func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp inter
 			Data: resp,
 		}
 
+		if s.client.rateLimiter != nil {
+			if err := s.client.rateLimiter.WaitGraphQL(ctx, s.client.shopDomain()); err != nil {
+				return err
+			}
+		}
+
 		err := s.client.Post(ctx, "graphql.json", data, &gr)
 
 		// internal attempts count towards outer total
@@ -89,9 +150,15 @@ func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp inter
 		var retryAfterSecs float64
 
 		if gr.Extensions != nil {
+			if recorder, ok := ctx.Value(graphQLCostKey{}).(*graphQLCostRecorder); ok {
+				cost := gr.Extensions.Cost
+				recorder.cost = &cost
+			}
 			retryAfterSecs = gr.Extensions.Cost.RetryAfterSeconds()
-			s.client.RateLimits.GraphQLCost = &gr.Extensions.Cost
-			s.client.RateLimits.RetryAfterSeconds = retryAfterSecs
+			s.client.setGraphQLRateLimits(gr.Extensions.Cost, retryAfterSecs)
+			if s.client.rateLimiter != nil {
+				s.client.rateLimiter.ObserveGraphQL(s.client.shopDomain(), gr.Extensions.Cost)
+			}
 		}
 
 		if len(gr.Errors) > 0 {
@@ -99,13 +166,14 @@ func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp inter
 			var doRetry bool
 
 			for _, err := range gr.Errors {
-				if err.Extensions != nil && err.Extensions.Code == graphQLErrorCodeThrottled {
-					if attempts >= s.client.retries {
+				if err.Code() == GraphQLErrorCodeThrottled {
+					if attempts >= s.client.retryPolicy.MaxAttempts {
 						return RateLimitError{
 							RetryAfter: int(math.Ceil(retryAfterSecs)),
 							ResponseError: ResponseError{
-								Status:  200,
-								Message: err.Message,
+								Status:        200,
+								Message:       err.Message,
+								GraphQLErrors: gr.Errors,
 							},
 						}
 					}
@@ -116,11 +184,15 @@ func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp inter
 
 				responseError.Errors = append(responseError.Errors, err.Message)
 			}
+			responseError.GraphQLErrors = gr.Errors
 
 			if doRetry {
 				wait := time.Duration(math.Ceil(retryAfterSecs)) * time.Second
 				s.client.log.Debugf("rate limited waiting %s", wait.String())
-				time.Sleep(wait)
+				info := RetryInfo{Attempt: attempts, StatusCode: http.StatusOK, Err: responseError, Delay: wait}
+				if err := s.client.retryPolicy.waitRetry(ctx, info); err != nil {
+					return err
+				}
 				continue
 			}
 
@@ -131,6 +203,112 @@ func (s *GraphQLServiceOp) Query(ctx context.Context, q string, vars, resp inter
 	}
 }
 
+type graphQLCostKey struct{}
+
+// graphQLCostRecorder receives the cost of the response of a Query run with
+// a context from withGraphQLCostRecorder, whatever other queries the client
+// runs meanwhile.
+type graphQLCostRecorder struct {
+	cost *GraphQLCost
+}
+
+func withGraphQLCostRecorder(ctx context.Context, recorder *graphQLCostRecorder) context.Context {
+	return context.WithValue(ctx, graphQLCostKey{}, recorder)
+}
+
+// Mutate runs a graphql mutation like Query. If the response has no errors
+// but a mutation payload reports userErrors, they are returned as UserErrors
+// while resp still holds the payload. See GraphQLMutate.
+func (s *GraphQLServiceOp) Mutate(ctx context.Context, m string, vars, resp interface{}) error {
+	return GraphQLMutate(ctx, s, m, vars, resp)
+}
+
+// GraphQLMutate runs a graphql mutation with the Query of gql, e.g. the
+// GraphQL service of a Client. If the response has no errors but mutation
+// payloads report userErrors, they are returned as UserErrors in the order
+// of the response while resp still holds the payloads.
+//
+//	err := goshopify.GraphQLMutate(ctx, client.GraphQL, mutation, vars, &resp)
+func GraphQLMutate(ctx context.Context, gql GraphQLService, m string, vars, resp interface{}) error {
+	var data json.RawMessage
+	if err := gql.Query(ctx, m, vars, &data); err != nil {
+		if len(data) > 0 && resp != nil {
+			if decodeErr := json.Unmarshal(data, resp); decodeErr != nil {
+				return fmt.Errorf("%w (decoding partial data: %v)", err, decodeErr)
+			}
+		}
+		return err
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if resp != nil {
+		if err := json.Unmarshal(data, resp); err != nil {
+			return err
+		}
+	}
+
+	if userErrors := FindUserErrors(data); len(userErrors) > 0 {
+		return userErrors
+	}
+
+	return nil
+}
+
+// FindUserErrors returns the user errors found in the "data" of a mutation
+// response, in any field named userErrors or ending in UserErrors, e.g.
+// customerUserErrors, in the order of the response.
+func FindUserErrors(data []byte) UserErrors {
+	var userErrors UserErrors
+	collectUserErrors(data, &userErrors)
+	return userErrors
+}
+
+// collectUserErrors walks the objects of data with a decoder rather than
+// through a map, so that user errors keep the order of the response.
+func collectUserErrors(data []byte, userErrors *UserErrors) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return
+			}
+			k, _ := tok.(string)
+
+			var elem json.RawMessage
+			if err := dec.Decode(&elem); err != nil {
+				return
+			}
+
+			if k == "userErrors" || strings.HasSuffix(k, "UserErrors") {
+				var found []GraphQLUserError
+				if json.Unmarshal(elem, &found) == nil {
+					*userErrors = append(*userErrors, found...)
+				}
+				continue
+			}
+			collectUserErrors(elem, userErrors)
+		}
+	case json.Delim('['):
+		for dec.More() {
+			var elem json.RawMessage
+			if err := dec.Decode(&elem); err != nil {
+				return
+			}
+			collectUserErrors(elem, userErrors)
+		}
+	}
+}
+
 // RetryAfterSeconds returns the estimated retry after seconds based on
 // the requested query cost and throttle status
 func (c GraphQLCost) RetryAfterSeconds() float64 {
@@ -0,0 +1,276 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GraphQLPageInfo is the pageInfo of a GraphQL connection
+type GraphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphQLConnection struct {
+	Edges []struct {
+		Node json.RawMessage `json:"node"`
+	} `json:"edges"`
+	Nodes    []json.RawMessage `json:"nodes"`
+	PageInfo *GraphQLPageInfo  `json:"pageInfo"`
+}
+
+// GraphQLPaginator walks a connection of a GraphQL query page by page. The
+// query takes the cursor of the next page in a variable, starting with null,
+// and selects either edges { node } or nodes of the connection along with
+// pageInfo { hasNextPage endCursor }.
+//
+// Before requesting the next page the paginator waits until the shop's
+// GraphQL bucket, as reported with the previous page, has restored the
+// actual cost of that page, so that walking a large connection does not run
+// into throttling. This is an estimate, pages can cost more than the
+// previous one and throttled queries are retried like any other.
+//
+//	q := `query products($cursor: String) {
+//		products(first: 250, after: $cursor) {
+//			nodes { id title }
+//			pageInfo { hasNextPage endCursor }
+//		}
+//	}`
+//	p := client.NewGraphQLPaginator(q, nil, "cursor", "products")
+//	for p.Next(ctx) {
+//		var product struct {
+//			Id    string `json:"id"`
+//			Title string `json:"title"`
+//		}
+//		if err := p.Decode(&product); err != nil {
+//			// handle error
+//		}
+//	}
+//	if err := p.Err(); err != nil {
+//		// handle error
+//	}
+//
+// A GraphQLPaginator is not safe for concurrent use.
+type GraphQLPaginator struct {
+	client         *Client
+	query          string
+	variables      map[string]interface{}
+	cursorVariable string
+	path           []string
+
+	nodes    []json.RawMessage
+	index    int
+	pageInfo GraphQLPageInfo
+
+	// cost of the previous page, nil if the response had none
+	cost *GraphQLCost
+
+	started bool
+	done    bool
+	err     error
+}
+
+// NewGraphQLPaginator returns a paginator over the connection at
+// connectionPath, the dot separated path of the connection in the query's
+// data, e.g. "products" or "order.lineItems". The cursor is passed in the
+// variable named cursorVariable, in addition to variables.
+func (c *Client) NewGraphQLPaginator(query string, variables map[string]interface{}, cursorVariable, connectionPath string) *GraphQLPaginator {
+	vars := make(map[string]interface{}, len(variables)+1)
+	for k, v := range variables {
+		vars[k] = v
+	}
+	vars[cursorVariable] = nil
+
+	p := &GraphQLPaginator{
+		client:         c,
+		query:          query,
+		variables:      vars,
+		cursorVariable: cursorVariable,
+	}
+
+	if connectionPath == "" {
+		p.err = errors.New("graphql paginator needs a connection path")
+		return p
+	}
+	p.path = strings.Split(connectionPath, ".")
+
+	return p
+}
+
+// NextPage fetches the next page. It returns false when there are no more
+// pages, when ctx is done or when a request fails; check Err to tell them
+// apart.
+func (p *GraphQLPaginator) NextPage(ctx context.Context) bool {
+	if p.err != nil || p.done {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	if p.started {
+		if err := p.waitForCost(ctx); err != nil {
+			p.err = err
+			return false
+		}
+	}
+
+	var data json.RawMessage
+	recorder := &graphQLCostRecorder{}
+	if err := p.client.GraphQL.Query(withGraphQLCostRecorder(ctx, recorder), p.query, p.variables, &data); err != nil {
+		p.err = err
+		return false
+	}
+	p.cost = recorder.cost
+
+	conn, err := p.connection(data)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	nodes := conn.Nodes
+	if len(conn.Edges) > 0 {
+		nodes = make([]json.RawMessage, len(conn.Edges))
+		for i, edge := range conn.Edges {
+			nodes[i] = edge.Node
+		}
+	}
+
+	p.started = true
+	p.nodes = nodes
+	p.index = -1
+	p.pageInfo = GraphQLPageInfo{}
+	if conn.PageInfo != nil {
+		p.pageInfo = *conn.PageInfo
+	}
+
+	if !p.pageInfo.HasNextPage || p.pageInfo.EndCursor == "" {
+		p.done = true
+	} else {
+		p.variables[p.cursorVariable] = p.pageInfo.EndCursor
+	}
+
+	return true
+}
+
+// connection returns the connection at the paginator's path in data
+func (p *GraphQLPaginator) connection(data json.RawMessage) (*graphQLConnection, error) {
+	raw := data
+	for _, field := range p.path {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil || obj == nil {
+			return nil, fmt.Errorf("graphql paginator: no connection at %s", strings.Join(p.path, "."))
+		}
+		var ok bool
+		if raw, ok = obj[field]; !ok {
+			return nil, fmt.Errorf("graphql paginator: no connection at %s", strings.Join(p.path, "."))
+		}
+	}
+
+	conn := &graphQLConnection{}
+	if err := json.Unmarshal(raw, conn); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// waitForCost waits until the GraphQL bucket reported with the previous page
+// holds enough points for another page of the same actual cost, or of its
+// requested cost if the actual one is unknown.
+func (p *GraphQLPaginator) waitForCost(ctx context.Context) error {
+	cost := p.cost
+	if cost == nil || cost.ThrottleStatus.RestoreRate <= 0 {
+		return nil
+	}
+
+	pageCost := float64(cost.RequestedQueryCost)
+	if cost.ActualQueryCost != nil {
+		pageCost = float64(*cost.ActualQueryCost)
+	}
+
+	missing := pageCost - cost.ThrottleStatus.CurrentlyAvailable
+	if missing <= 0 {
+		return nil
+	}
+
+	wait := secondsToDuration(missing / cost.ThrottleStatus.RestoreRate)
+	p.client.log.Debugf("graphql paginator waiting %s for query cost", wait.Round(time.Millisecond))
+	return sleepContext(ctx, wait)
+}
+
+// PageInfo returns the pageInfo of the current page
+func (p *GraphQLPaginator) PageInfo() GraphQLPageInfo {
+	return p.pageInfo
+}
+
+// Nodes returns the raw nodes of the current page
+func (p *GraphQLPaginator) Nodes() []json.RawMessage {
+	return p.nodes
+}
+
+// Next advances to the next node, fetching a new page when the current one
+// is exhausted. It returns false when there are no more nodes, when ctx is
+// done or when a request fails; check Err to tell them apart.
+func (p *GraphQLPaginator) Next(ctx context.Context) bool {
+	if p.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	for {
+		if p.started && p.index+1 < len(p.nodes) {
+			p.index++
+			return true
+		}
+		if !p.NextPage(ctx) {
+			return false
+		}
+	}
+}
+
+// Node returns the raw JSON of the current node
+func (p *GraphQLPaginator) Node() json.RawMessage {
+	if !p.started || p.index < 0 || p.index >= len(p.nodes) {
+		return nil
+	}
+	return p.nodes[p.index]
+}
+
+// Decode unmarshals the current node into v
+func (p *GraphQLPaginator) Decode(v interface{}) error {
+	node := p.Node()
+	if node == nil {
+		return errors.New("graphql paginator: no current node")
+	}
+	return json.Unmarshal(node, v)
+}
+
+// Err returns the error that stopped the iteration, if any. It is
+// context.Canceled or context.DeadlineExceeded when ctx was done.
+func (p *GraphQLPaginator) Err() error {
+	return p.err
+}
+
+// Each calls fn for every remaining node. Returning a non-nil error from fn
+// stops the iteration and that error is returned; ErrStopIteration stops it
+// without an error.
+func (p *GraphQLPaginator) Each(ctx context.Context, fn func(node json.RawMessage) error) error {
+	for p.Next(ctx) {
+		if err := fn(p.Node()); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return p.Err()
+}
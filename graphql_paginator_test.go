@@ -0,0 +1,192 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+const paginatorQuery = `query products($cursor: String, $status: String) {
+	shop { products(first: 2, after: $cursor, query: $status) {
+		edges { node { id } }
+		pageInfo { hasNextPage endCursor }
+	} }
+}`
+
+func registerProductPages(t *testing.T, cost string) {
+	t.Helper()
+
+	pages := map[interface{}]string{
+		nil:   `{"edges":[{"node":{"id":"gid://shopify/Product/1"}},{"node":{"id":"gid://shopify/Product/2"}}],"pageInfo":{"hasNextPage":true,"endCursor":"c2"}}`,
+		"c2":  `{"edges":[],"pageInfo":{"hasNextPage":true,"endCursor":"c2b"}}`,
+		"c2b": `{"edges":[{"node":{"id":"gid://shopify/Product/3"}}],"pageInfo":{"hasNextPage":false,"endCursor":"c3"}}`,
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			gr := decodeGraphQLRequest(t, req)
+			if gr.Variables["status"] != "active" {
+				t.Errorf("GraphQLPaginator lost the query variables %v", gr.Variables)
+			}
+			page, ok := pages[gr.Variables["cursor"]]
+			if !ok {
+				t.Fatalf("GraphQLPaginator requested unexpected cursor %v", gr.Variables["cursor"])
+			}
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"data":{"shop":{"products":%s}},"extensions":{"cost":%s}}`, page, cost)), nil
+		})
+}
+
+func TestGraphQLPaginatorNext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerProductPages(t, `{"requestedQueryCost":10,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":990,"restoreRate":50}}`)
+
+	p := client.NewGraphQLPaginator(paginatorQuery, map[string]interface{}{"status": "active"}, "cursor", "shop.products")
+
+	var ids []string
+	for p.Next(context.Background()) {
+		var product struct {
+			Id string `json:"id"`
+		}
+		if err := p.Decode(&product); err != nil {
+			t.Fatalf("GraphQLPaginator.Decode() returned %v", err)
+		}
+		ids = append(ids, product.Id)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("GraphQLPaginator.Err() returned %v", err)
+	}
+
+	expected := []string{"gid://shopify/Product/1", "gid://shopify/Product/2", "gid://shopify/Product/3"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("GraphQLPaginator.Next() yielded %v, expected %v", ids, expected)
+	}
+
+	if p.Next(context.Background()) {
+		t.Errorf("GraphQLPaginator.Next() returned true after the last page")
+	}
+
+	if info := p.PageInfo(); info.HasNextPage || info.EndCursor != "c3" {
+		t.Errorf("GraphQLPaginator.PageInfo() returned %+v", info)
+	}
+}
+
+func TestGraphQLPaginatorNodes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"orders":{"nodes":[{"id":"1"},{"id":"2"}],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}`))
+
+	p := client.NewGraphQLPaginator("query($after: String) { orders(first: 2, after: $after) { nodes { id } pageInfo { hasNextPage endCursor } } }", nil, "after", "orders")
+
+	var nodes []string
+	err := p.Each(context.Background(), func(node json.RawMessage) error {
+		nodes = append(nodes, string(node))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GraphQLPaginator.Each() returned %v", err)
+	}
+
+	expected := []string{`{"id":"1"}`, `{"id":"2"}`}
+	if !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("GraphQLPaginator.Each() yielded %v, expected %v", nodes, expected)
+	}
+}
+
+func TestGraphQLPaginatorWaitsForCost(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// each page leaves 50 points missing, restored in 50ms
+	registerProductPages(t, `{"requestedQueryCost":100,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":50,"restoreRate":1000}}`)
+
+	p := client.NewGraphQLPaginator(paginatorQuery, map[string]interface{}{"status": "active"}, "cursor", "shop.products")
+
+	start := time.Now()
+	pages := 0
+	for p.NextPage(context.Background()) {
+		pages++
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("GraphQLPaginator.Err() returned %v", err)
+	}
+
+	if pages != 3 {
+		t.Errorf("GraphQLPaginator.NextPage() fetched %d pages, expected 3", pages)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("GraphQLPaginator did not wait for the query cost, took %s", elapsed)
+	}
+}
+
+func TestGraphQLPaginatorWaitsForOwnActualCost(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// pages request 100 points but cost 10, the bucket holds 50 restored at
+	// 1 point per second
+	registerProductPages(t, `{"requestedQueryCost":100,"actualQueryCost":10,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":50,"restoreRate":1}}`)
+
+	p := client.NewGraphQLPaginator(paginatorQuery, map[string]interface{}{"status": "active"}, "cursor", "shop.products")
+
+	start := time.Now()
+	if !p.NextPage(context.Background()) {
+		t.Fatalf("GraphQLPaginator.NextPage() returned %v", p.Err())
+	}
+
+	// another query of the client leaves the bucket empty
+	client.setGraphQLRateLimits(GraphQLCost{RequestedQueryCost: 1000, ThrottleStatus: GraphQLThrottleStatus{MaximumAvailable: 1000, RestoreRate: 1}}, 1000)
+
+	pages := 1
+	for p.NextPage(context.Background()) {
+		pages++
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("GraphQLPaginator.Err() returned %v", err)
+	}
+
+	if pages != 3 {
+		t.Errorf("GraphQLPaginator.NextPage() fetched %d pages, expected 3", pages)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GraphQLPaginator waited for the requested cost or another query, took %s", elapsed)
+	}
+}
+
+func TestGraphQLPaginatorErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"shop":null}}`))
+
+	p := client.NewGraphQLPaginator(paginatorQuery, nil, "cursor", "shop.products")
+	if p.Next(context.Background()) {
+		t.Errorf("GraphQLPaginator.Next() returned true without a connection")
+	}
+	if p.Err() == nil {
+		t.Errorf("GraphQLPaginator.Err() expected an error for a missing connection")
+	}
+
+	p = client.NewGraphQLPaginator(paginatorQuery, nil, "cursor", "")
+	if p.Next(context.Background()) || p.Err() == nil {
+		t.Errorf("GraphQLPaginator expected an error for an empty connection path")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p = client.NewGraphQLPaginator(paginatorQuery, nil, "cursor", "shop.products")
+	if p.Next(ctx) || !errors.Is(p.Err(), context.Canceled) {
+		t.Errorf("GraphQLPaginator.Err() returned %v, expected %v", p.Err(), context.Canceled)
+	}
+}
@@ -2,9 +2,12 @@ package goshopify
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -105,6 +108,10 @@ func TestGraphQLQueryWithRetries(t *testing.T) {
 				ResponseError: ResponseError{
 					Status:  200,
 					Message: "Throttled",
+					GraphQLErrors: []GraphQLError{{
+						Message:    "Throttled",
+						Extensions: &GraphQLErrorExtensions{Code: GraphQLErrorCodeThrottled},
+					}},
 				},
 				RetryAfter: 2,
 			},
@@ -215,6 +222,216 @@ func TestGraphQLQueryWithRetries(t *testing.T) {
 	}
 }
 
+func TestGraphQLQueryWithTypedErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{
+			"data":{"foo":"bar","baz":null},
+			"errors":[{
+				"message":"Access denied for baz field.",
+				"locations":[{"line":1,"column":13}],
+				"path":["baz",0,"qux"],
+				"extensions":{"code":"ACCESS_DENIED","documentation":"https://shopify.dev/api/usage/access-scopes"}
+			}]
+		}`),
+	)
+
+	resp := struct {
+		Foo string `json:"foo"`
+	}{}
+	err := client.GraphQL.Query(context.Background(), "query { foo baz { qux } }", nil, &resp)
+
+	var respErr ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("GraphQL.Query returned %#v, expected a ResponseError", err)
+	}
+
+	expected := []GraphQLError{{
+		Message:   "Access denied for baz field.",
+		Locations: []GraphQLErrorLocation{{Line: 1, Column: 13}},
+		Path:      []interface{}{"baz", float64(0), "qux"},
+		Extensions: &GraphQLErrorExtensions{
+			Code:          GraphQLErrorCodeAccessDenied,
+			Documentation: "https://shopify.dev/api/usage/access-scopes",
+		},
+	}}
+	if !reflect.DeepEqual(respErr.GetGraphQLErrors(), expected) {
+		t.Errorf("GraphQL.Query returned errors %#v, expected %#v", respErr.GetGraphQLErrors(), expected)
+	}
+	if respErr.GetGraphQLErrors()[0].Code() != GraphQLErrorCodeAccessDenied {
+		t.Errorf("GraphQLError.Code returned %s, expected %s", respErr.GetGraphQLErrors()[0].Code(), GraphQLErrorCodeAccessDenied)
+	}
+
+	// partial data is returned along with the errors
+	if resp.Foo != "bar" {
+		t.Errorf("GraphQL.Query returned partial data %#v, expected foo bar", resp)
+	}
+}
+
+func TestGraphQLMutateWithUserErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{
+			"data":{"productCreate":{
+				"product":null,
+				"userErrors":[{"field":["input","title"],"message":"Title can't be blank"}]
+			}}
+		}`),
+	)
+
+	resp := struct {
+		ProductCreate struct {
+			Product *struct {
+				Id string `json:"id"`
+			} `json:"product"`
+		} `json:"productCreate"`
+	}{}
+	err := GraphQLMutate(context.Background(), client.GraphQL, "mutation { productCreate(input: {}) { product { id } userErrors { field message } } }", nil, &resp)
+
+	var userErrors UserErrors
+	if !errors.As(err, &userErrors) {
+		t.Fatalf("GraphQLMutate returned %#v, expected UserErrors", err)
+	}
+
+	expected := UserErrors{{Field: []string{"input", "title"}, Message: "Title can't be blank"}}
+	if !reflect.DeepEqual(userErrors, expected) {
+		t.Errorf("GraphQLMutate returned %#v, expected %#v", userErrors, expected)
+	}
+
+	expectedError := "input.title: Title can't be blank"
+	if err.Error() != expectedError {
+		t.Errorf("GraphQLMutate returned error message %s but expected %s", err.Error(), expectedError)
+	}
+}
+
+func TestGraphQLMutate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{"tagsAdd":{"node":{"id":"gid://shopify/Order/1"},"userErrors":[]}}}`),
+	)
+
+	resp := struct {
+		TagsAdd struct {
+			Node struct {
+				Id string `json:"id"`
+			} `json:"node"`
+		} `json:"tagsAdd"`
+	}{}
+	err := client.GraphQL.(*GraphQLServiceOp).Mutate(context.Background(), "mutation { tagsAdd { node { id } userErrors { field message } } }", nil, &resp)
+	if err != nil {
+		t.Fatalf("GraphQLMutate returned error: %v", err)
+	}
+
+	if resp.TagsAdd.Node.Id != "gid://shopify/Order/1" {
+		t.Errorf("GraphQLMutate returned %#v", resp)
+	}
+}
+
+func TestGraphQLMutateWithErrorsAndUndecodablePartialData(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{
+			"data":{"tagsAdd":{"node":{"id":1}}},
+			"errors":[{"message":"Access denied","extensions":{"code":"ACCESS_DENIED"}}]
+		}`),
+	)
+
+	resp := struct {
+		TagsAdd struct {
+			Node struct {
+				Id string `json:"id"`
+			} `json:"node"`
+		} `json:"tagsAdd"`
+	}{}
+	err := GraphQLMutate(context.Background(), client.GraphQL, "mutation { tagsAdd { node { id } } }", nil, &resp)
+
+	var respErr ResponseError
+	if !errors.As(err, &respErr) || len(respErr.GraphQLErrors) != 1 {
+		t.Fatalf("GraphQLMutate returned %#v, expected a ResponseError", err)
+	}
+
+	if !strings.Contains(err.Error(), "decoding partial data") {
+		t.Errorf("GraphQLMutate returned error message %s, expected the decoding error", err.Error())
+	}
+}
+
+func TestGraphQLMutateWithAliasedUserErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder(
+		"POST",
+		fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{
+			"c":{"userErrors":[{"field":["id"],"message":"c"}]},
+			"a":{"userErrors":[{"field":["id"],"message":"a"}]},
+			"b":{"userErrors":[{"field":["id"],"message":"b"}]}
+		}}`),
+	)
+
+	// in the order of the response
+	expectedError := "id: c, id: a, id: b"
+	for i := 0; i < 10; i++ {
+		err := GraphQLMutate(context.Background(), client.GraphQL, "mutation { a: tagsAdd { userErrors { field message } } }", nil, nil)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("GraphQLMutate returned error %v but expected %s", err, expectedError)
+		}
+	}
+}
+
+// fakeGraphQLService is an implementation of GraphQLService outside of the
+// package, e.g. a mock, answering every query with data.
+type fakeGraphQLService struct {
+	data string
+}
+
+func (s fakeGraphQLService) Query(ctx context.Context, q string, vars, resp interface{}) error {
+	return json.Unmarshal([]byte(s.data), resp)
+}
+
+func TestGraphQLMutateWithGraphQLService(t *testing.T) {
+	gql := fakeGraphQLService{data: `{"tagsAdd":{"node":null,"userErrors":[{"field":["id"],"message":"Order does not exist"}]}}`}
+
+	resp := struct {
+		TagsAdd struct {
+			Node *struct {
+				Id string `json:"id"`
+			} `json:"node"`
+		} `json:"tagsAdd"`
+	}{}
+	err := GraphQLMutate(context.Background(), gql, "mutation { tagsAdd { node { id } userErrors { field message } } }", nil, &resp)
+
+	expected := UserErrors{{Field: []string{"id"}, Message: "Order does not exist"}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("GraphQLMutate returned %#v, expected %#v", err, expected)
+	}
+}
+
+func TestFindUserErrors(t *testing.T) {
+	data := []byte(`{"customerCreate":{"customerUserErrors":[{"field":["email"],"message":"Email has already been taken","code":"TAKEN"}]}}`)
+
+	expected := UserErrors{{Field: []string{"email"}, Message: "Email has already been taken", Code: "TAKEN"}}
+	if actual := FindUserErrors(data); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("FindUserErrors returned %#v, expected %#v", actual, expected)
+	}
+}
+
 func TestGraphQLQueryWithMultipleErrors(t *testing.T) {
 	setup()
 	defer teardown()
@@ -243,7 +460,7 @@ func TestGraphQLQueryWithMultipleErrors(t *testing.T) {
 func TestGraphQLQueryWithThrottledError(t *testing.T) {
 	setup()
 	defer teardown()
-	client.retries = 1
+	client.retryPolicy.MaxAttempts = 1
 
 	httpmock.RegisterResponder(
 		"POST",
@@ -288,12 +505,12 @@ func TestGraphQLQueryWithThrottledError(t *testing.T) {
 		t.Errorf("GraphQL.Query rle.RetryAfter is %d but expected %d", rle.RetryAfter, int(expectedRetryAfterSeconds))
 	}
 
-	if client.RateLimits.GraphQLCost == nil {
-		t.Errorf("GraphQL.Query should have assigned client.RateLimits.GraphQLCost")
+	if client.RateLimitSnapshot().GraphQLCost == nil {
+		t.Errorf("GraphQL.Query should have assigned client.RateLimitSnapshot().GraphQLCost")
 	}
 
-	if client.RateLimits.RetryAfterSeconds != expectedRetryAfterSeconds {
-		t.Errorf("GraphQL.Query client.RateLimits.RetryAfterSeconds is %f but expected %f", client.RateLimits.RetryAfterSeconds, expectedRetryAfterSeconds)
+	if client.RateLimitSnapshot().RetryAfterSeconds != expectedRetryAfterSeconds {
+		t.Errorf("GraphQL.Query client.RateLimitSnapshot().RetryAfterSeconds is %f but expected %f", client.RateLimitSnapshot().RetryAfterSeconds, expectedRetryAfterSeconds)
 	}
 }
 
@@ -0,0 +1,176 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ListIterator lazily walks a paginated list endpoint of the Shopify API.
+// Pages are requested one at a time with Client.ListWithPagination, so only
+// the current page is held in memory no matter how large the collection is.
+//
+// The resource argument of NewListIterator is the root object of the list
+// endpoint, e.g. ProductsResource or WebhooksResource. Its slice field holds
+// the items of a page.
+//
+//	it := client.NewListIterator("products.json", new(ProductsResource), ListOptions{Limit: 250})
+//	for it.Next(ctx) {
+//		product := it.Item().(Product)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+//
+// A ListIterator is not safe for concurrent use.
+type ListIterator struct {
+	client       *Client
+	path         string
+	options      interface{}
+	resourceType reflect.Type
+	itemsField   int
+
+	page  reflect.Value
+	items reflect.Value
+	index int
+
+	started bool
+	done    bool
+	err     error
+}
+
+// NewListIterator returns an iterator over the list endpoint at path. The
+// options are used for the first page only, subsequent pages are requested
+// with the options returned in the Link header.
+func (c *Client) NewListIterator(path string, resource, options interface{}) *ListIterator {
+	it := &ListIterator{
+		client:  c,
+		path:    path,
+		options: options,
+	}
+
+	t := reflect.TypeOf(resource)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		it.err = fmt.Errorf("list iterator resource must be a struct, got %T", resource)
+		return it
+	}
+
+	it.itemsField = -1
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath == "" && f.Type.Kind() == reflect.Slice {
+			it.itemsField = i
+			break
+		}
+	}
+	if it.itemsField < 0 {
+		it.err = fmt.Errorf("list iterator resource %s has no slice field", t.Name())
+		return it
+	}
+
+	it.resourceType = t
+	return it
+}
+
+// NextPage fetches the next page. It returns false when there are no more
+// pages, when ctx is done or when a request fails; check Err to tell them
+// apart.
+func (it *ListIterator) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	page := reflect.New(it.resourceType)
+	pagination, err := it.client.ListWithPagination(ctx, it.path, page.Interface(), it.options)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.started = true
+	it.page = page
+	it.items = page.Elem().Field(it.itemsField)
+	it.index = -1
+
+	if pagination.NextPageOptions == nil {
+		it.done = true
+	} else {
+		it.options = pagination.NextPageOptions
+	}
+
+	return true
+}
+
+// Page returns the current page, a pointer to the resource type passed to
+// NewListIterator.
+func (it *ListIterator) Page() interface{} {
+	if !it.page.IsValid() {
+		return nil
+	}
+	return it.page.Interface()
+}
+
+// Next advances to the next item, fetching a new page when the current one
+// is exhausted. It returns false when there are no more items, when ctx is
+// done or when a request fails; check Err to tell them apart.
+func (it *ListIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for {
+		if it.started && it.index+1 < it.items.Len() {
+			it.index++
+			return true
+		}
+		if !it.NextPage(ctx) {
+			return false
+		}
+	}
+}
+
+// Item returns the current item. The dynamic type is the element type of the
+// resource's slice field, e.g. Product for ProductsResource.
+func (it *ListIterator) Item() interface{} {
+	if !it.started || it.index < 0 || it.index >= it.items.Len() {
+		return nil
+	}
+	return it.items.Index(it.index).Interface()
+}
+
+// Err returns the error that stopped the iteration, if any. It is
+// context.Canceled or context.DeadlineExceeded when ctx was done.
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+// ErrStopIteration can be returned from the callback of ListIterator.Each to
+// stop iterating without reporting an error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// Each calls fn for every remaining item. Returning a non-nil error from fn
+// stops the iteration and that error is returned; ErrStopIteration stops it
+// without an error.
+func (it *ListIterator) Each(ctx context.Context, fn func(item interface{}) error) error {
+	for it.Next(ctx) {
+		if err := fn(it.Item()); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return it.Err()
+}
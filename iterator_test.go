@@ -0,0 +1,185 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func registerWebhookPages(t *testing.T) {
+	t.Helper()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/webhooks.json", client.pathPrefix)
+
+	pages := []struct {
+		url  string
+		link string
+		body string
+	}{
+		{listURL, `<http://valid.url?page_info=pg2&limit=2>; rel="next"`, `{"webhooks": [{"id":1},{"id":2}]}`},
+		{listURL + "?limit=2&page_info=pg2", `<http://valid.url?page_info=pg1>; rel="previous", <http://valid.url?page_info=pg3&limit=2>; rel="next"`, `{"webhooks": []}`},
+		{listURL + "?limit=2&page_info=pg3", `<http://valid.url?page_info=pg2>; rel="previous"`, `{"webhooks": [{"id":3}]}`},
+	}
+
+	for _, p := range pages {
+		response := &http.Response{
+			StatusCode: 200,
+			Body:       httpmock.NewRespBodyFromString(p.body),
+			Header:     http.Header{"Link": {p.link}},
+		}
+		httpmock.RegisterResponder("GET", p.url, httpmock.ResponderFromResponse(response))
+	}
+}
+
+func TestListIteratorNext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerWebhookPages(t)
+
+	it := client.NewListIterator("webhooks.json", new(WebhooksResource), nil)
+
+	var ids []uint64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().(Webhook).Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("ListIterator.Err() returned %v", err)
+	}
+
+	expected := []uint64{1, 2, 3}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("ListIterator.Next() yielded %v, expected %v", ids, expected)
+	}
+
+	if it.Next(context.Background()) {
+		t.Error("ListIterator.Next() returned true after the last item")
+	}
+}
+
+func TestListIteratorNextPage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerWebhookPages(t)
+
+	it := client.NewListIterator("webhooks.json", WebhooksResource{}, nil)
+
+	var sizes []int
+	for it.NextPage(context.Background()) {
+		sizes = append(sizes, len(it.Page().(*WebhooksResource).Webhooks))
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("ListIterator.Err() returned %v", err)
+	}
+
+	expected := []int{2, 0, 1}
+	if !reflect.DeepEqual(sizes, expected) {
+		t.Errorf("ListIterator.NextPage() page sizes %v, expected %v", sizes, expected)
+	}
+}
+
+func TestListIteratorEach(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerWebhookPages(t)
+
+	var ids []uint64
+	err := client.NewListIterator("webhooks.json", new(WebhooksResource), nil).
+		Each(context.Background(), func(item interface{}) error {
+			ids = append(ids, item.(Webhook).Id)
+			if len(ids) == 2 {
+				return ErrStopIteration
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("ListIterator.Each() returned %v", err)
+	}
+
+	if len(ids) != 2 {
+		t.Errorf("ListIterator.Each() visited %d items, expected 2", len(ids))
+	}
+
+	// Only the first page must have been requested
+	if n := httpmock.GetTotalCallCount(); n != 1 {
+		t.Errorf("ListIterator.Each() made %d requests, expected 1", n)
+	}
+
+	expectedErr := errors.New("boom")
+	err = client.NewListIterator("webhooks.json", new(WebhooksResource), nil).
+		Each(context.Background(), func(item interface{}) error {
+			return expectedErr
+		})
+	if err != expectedErr {
+		t.Errorf("ListIterator.Each() returned %v, expected %v", err, expectedErr)
+	}
+}
+
+func TestListIteratorContextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerWebhookPages(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	it := client.NewListIterator("webhooks.json", new(WebhooksResource), nil)
+	if !it.Next(ctx) {
+		t.Fatalf("ListIterator.Next() returned false, err %v", it.Err())
+	}
+
+	cancel()
+
+	if it.Next(ctx) {
+		t.Error("ListIterator.Next() returned true after cancel")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("ListIterator.Err() returned %v, expected %v", it.Err(), context.Canceled)
+	}
+}
+
+func TestListIteratorError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/webhooks.json", client.pathPrefix),
+		httpmock.NewStringResponder(500, `{"errors":"oops"}`))
+
+	it := client.NewListIterator("webhooks.json", new(WebhooksResource), nil)
+	if it.Next(context.Background()) {
+		t.Fatal("ListIterator.Next() returned true on error")
+	}
+
+	expected := "oops"
+	if it.Err() == nil || it.Err().Error() != expected {
+		t.Errorf("ListIterator.Err() returned %v, expected %s", it.Err(), expected)
+	}
+}
+
+func TestListIteratorInvalidResource(t *testing.T) {
+	cases := []struct {
+		resource interface{}
+		expected string
+	}{
+		{nil, "list iterator resource must be a struct, got <nil>"},
+		{[]Webhook{}, "list iterator resource must be a struct, got []goshopify.Webhook"},
+		{WebhookResource{}, "list iterator resource WebhookResource has no slice field"},
+	}
+
+	for _, c := range cases {
+		it := client.NewListIterator("webhooks.json", c.resource, nil)
+		if it.Next(context.Background()) {
+			t.Errorf("ListIterator.Next() returned true for %T", c.resource)
+		}
+		if it.Err() == nil || it.Err().Error() != c.expected {
+			t.Errorf("ListIterator.Err() returned %v, expected %s", it.Err(), c.expected)
+		}
+	}
+}
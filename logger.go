@@ -1,6 +1,7 @@
 package goshopify
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -22,6 +23,16 @@ type LeveledLoggerInterface interface {
 	Warnf(format string, v ...interface{})
 }
 
+// StructuredLogger receives one record with key/value fields per API call,
+// in the style of log/slog. Levels are LevelError to LevelDebug.
+// NewSlogLogger adapts a *slog.Logger, see WithStructuredLogger.
+type StructuredLogger interface {
+	// Enabled reports whether records of level are logged. Headers and
+	// bodies are only collected when LevelDebug is enabled.
+	Enabled(ctx context.Context, level int) bool
+	Log(ctx context.Context, level int, msg string, keyvals ...interface{})
+}
+
 // It prints warnings and errors to `os.Stderr` and other messages to
 // `os.Stdout`.
 type LeveledLogger struct {
@@ -0,0 +1,38 @@
+//go:build go1.21
+
+package goshopify
+
+import (
+	"context"
+	"log/slog"
+)
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a StructuredLogger writing to logger.
+func NewSlogLogger(logger *slog.Logger) StructuredLogger {
+	return &slogLogger{logger: logger}
+}
+
+func (l *slogLogger) Enabled(ctx context.Context, level int) bool {
+	return l.logger.Enabled(ctx, slogLevel(level))
+}
+
+func (l *slogLogger) Log(ctx context.Context, level int, msg string, keyvals ...interface{}) {
+	l.logger.Log(ctx, slogLevel(level), msg, keyvals...)
+}
+
+func slogLevel(level int) slog.Level {
+	switch level {
+	case LevelError:
+		return slog.LevelError
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.21
+
+package goshopify
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	ctx := context.Background()
+	if logger.Enabled(ctx, LevelInfo) {
+		t.Errorf("NewSlogLogger enabled info level below the handler level")
+	}
+	if !logger.Enabled(ctx, LevelError) {
+		t.Errorf("NewSlogLogger disabled error level")
+	}
+
+	logger.Log(ctx, LevelWarn, "shopify request", "status", 422)
+
+	actual := buf.String()
+	if !strings.Contains(actual, "level=WARN") || !strings.Contains(actual, "status=422") {
+		t.Errorf("NewSlogLogger wrote %q", actual)
+	}
+}
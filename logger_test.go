@@ -2,12 +2,16 @@ package goshopify
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/jarcoal/httpmock"
 )
 
 func TestLeveledLogger(t *testing.T) {
@@ -123,3 +127,99 @@ func TestDoGetHeadersDebug(t *testing.T) {
 		t.Errorf("doGetHeadersDebug expected stdout \"%s\" received \"%s\"", resExpected, out.String())
 	}
 }
+
+type structuredRecord struct {
+	level   int
+	msg     string
+	keyvals map[string]interface{}
+}
+
+type recordingLogger struct {
+	level   int
+	records []structuredRecord
+}
+
+func (l *recordingLogger) Enabled(ctx context.Context, level int) bool {
+	return level <= l.level
+}
+
+func (l *recordingLogger) Log(ctx context.Context, level int, msg string, keyvals ...interface{}) {
+	r := structuredRecord{level: level, msg: msg, keyvals: map[string]interface{}{}}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		r.keyvals[keyvals[i].(string)] = keyvals[i+1]
+	}
+	l.records = append(l.records, r)
+}
+
+func TestStructuredLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingLogger{level: LevelDebug}
+	WithStructuredLogger(logger)(client)
+
+	httpmock.RegisterResponder("PUT", fmt.Sprintf("https://fooshop.myshopify.com/%s/customers/1.json", client.pathPrefix),
+		httpmock.NewStringResponder(422, `{"errors":{"base":["is invalid"]}}`).HeaderSet(http.Header{"X-Request-Id": {"req-1"}}))
+
+	_, err := client.Customer.Update(context.Background(), Customer{Id: 1, Email: "bob@example.com"})
+	if err == nil {
+		t.Fatal("Customer.Update expected an error")
+	}
+
+	if len(logger.records) != 1 {
+		t.Fatalf("structured logger received %d records, expected 1", len(logger.records))
+	}
+
+	r := logger.records[0]
+	if r.level != LevelWarn {
+		t.Errorf("structured record level %d, expected %d", r.level, LevelWarn)
+	}
+
+	expected := map[string]interface{}{
+		"method":     "PUT",
+		"path":       fmt.Sprintf("/%s/customers/1.json", client.pathPrefix),
+		"shop":       "fooshop.myshopify.com",
+		"status":     422,
+		"attempt":    1,
+		"request_id": "req-1",
+	}
+	for k, v := range expected {
+		if r.keyvals[k] != v {
+			t.Errorf("structured record %s = %v, expected %v", k, r.keyvals[k], v)
+		}
+	}
+
+	if h := r.keyvals["request_headers"].(http.Header); h.Get("X-Shopify-Access-Token") != "[REDACTED]" {
+		t.Errorf("structured record leaked the access token %v", h)
+	}
+	for _, k := range []string{"request_body", "response_body"} {
+		if body := r.keyvals[k].(string); strings.Contains(body, "bob@example.com") || body == "" {
+			t.Errorf("structured record %s = %s, expected a redacted body", k, body)
+		}
+	}
+	if !strings.Contains(r.keyvals["response_body"].(string), "is invalid") {
+		t.Errorf("structured record response_body = %s", r.keyvals["response_body"])
+	}
+}
+
+func TestStructuredLoggerInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	logger := &recordingLogger{level: LevelInfo}
+	WithStructuredLogger(logger)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shop.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("shop.json")))
+
+	if _, err := client.Shop.Get(context.Background(), nil); err != nil {
+		t.Fatalf("Shop.Get returned error: %v", err)
+	}
+
+	if len(logger.records) != 1 || logger.records[0].level != LevelInfo {
+		t.Fatalf("structured logger received %+v, expected one info record", logger.records)
+	}
+	if _, ok := logger.records[0].keyvals["response_body"]; ok {
+		t.Errorf("structured record contains the body below debug level")
+	}
+}
@@ -0,0 +1,75 @@
+package goshopify
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RoundTripFunc sends a single HTTP request to Shopify and returns its
+// response.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the RoundTripFunc of a client to observe or alter every
+// request and response, see WithMiddleware.
+//
+//	func Audit(next goshopify.RoundTripFunc) goshopify.RoundTripFunc {
+//		return func(req *http.Request) (*http.Response, error) {
+//			resp, err := next(req)
+//			// inspect req and resp
+//			return resp, err
+//		}
+//	}
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// roundTrip sends req through the middleware chain of the client. The first
+// middleware passed to WithMiddleware is the outermost one.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	next := RoundTripFunc(c.Client.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next(req)
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the given request id,
+// see RequestIDMiddleware.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id stored in ctx by
+// ContextWithRequestID, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDMiddleware propagates the request id of the request's context to
+// Shopify in the given header, e.g. "X-Request-Id", so that calls can be
+// correlated with the work that triggered them.
+func RequestIDMiddleware(header string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if id := RequestIDFromContext(req.Context()); id != "" && req.Header.Get(header) == "" {
+				req.Header.Set(header, id)
+			}
+			return next(req)
+		}
+	}
+}
+
+// TimingMiddleware calls observe with the duration of every round trip,
+// including its response or error.
+func TimingMiddleware(observe func(req *http.Request, resp *http.Response, err error, duration time.Duration)) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			observe(req, resp, err, time.Since(start))
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+req.Method+" "+req.URL.Path)
+				resp, err := next(req)
+				calls = append(calls, fmt.Sprintf("%s %d", name, resp.StatusCode))
+				return resp, err
+			}
+		}
+	}
+	WithMiddleware(record("outer"), record("inner"))(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shop.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"shop":{"id":1}}`))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{}}`))
+
+	if _, err := client.Shop.Get(context.Background(), nil); err != nil {
+		t.Fatalf("Shop.Get() returned %v", err)
+	}
+	if err := client.GraphQL.Query(context.Background(), "query {}", nil, nil); err != nil {
+		t.Fatalf("GraphQL.Query() returned %v", err)
+	}
+
+	expected := []string{
+		"outer GET /admin/api/9999-99/shop.json",
+		"inner GET /admin/api/9999-99/shop.json",
+		"inner 200",
+		"outer 200",
+		"outer POST /admin/api/9999-99/graphql.json",
+		"inner POST /admin/api/9999-99/graphql.json",
+		"inner 200",
+		"outer 200",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("middleware calls %v, expected %v", calls, expected)
+	}
+}
+
+func TestWithMiddlewareSeesRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var statuses []int
+	WithMiddleware(TimingMiddleware(func(req *http.Request, resp *http.Response, err error, d time.Duration) {
+		statuses = append(statuses, resp.StatusCode)
+	}))(client)
+
+	attempts := 0
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/foo",
+		func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+			}
+			return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+		})
+
+	req, _ := client.NewRequest(context.Background(), "GET", "foo", nil, nil)
+	if err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do() returned %v", err)
+	}
+
+	expected := []int{http.StatusServiceUnavailable, http.StatusOK}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("TimingMiddleware observed %v, expected %v", statuses, expected)
+	}
+}
+
+func TestWithMiddlewareShortCircuit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	cached := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(http.StatusOK, `{"shop":{"id":42}}`), nil
+		}
+	}
+	WithMiddleware(cached)(client)
+
+	shop, err := client.Shop.Get(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Shop.Get() returned %v", err)
+	}
+	if shop.Id != 42 {
+		t.Errorf("Shop.Get() returned %+v, expected the cached shop", shop)
+	}
+	if n := httpmock.GetTotalCallCount(); n != 0 {
+		t.Errorf("made %d calls, expected none", n)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	WithMiddleware(RequestIDMiddleware("X-Request-Id"))(client)
+
+	var received []string
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/foo",
+		func(req *http.Request) (*http.Response, error) {
+			received = append(received, req.Header.Get("X-Request-Id"))
+			return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+		})
+
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	if id := RequestIDFromContext(ctx); id != "req-123" {
+		t.Errorf("RequestIDFromContext() = %s, expected req-123", id)
+	}
+
+	for _, ctx := range []context.Context{ctx, context.Background()} {
+		req, _ := client.NewRequest(ctx, "GET", "foo", nil, nil)
+		if err := client.Do(req, nil); err != nil {
+			t.Fatalf("Do() returned %v", err)
+		}
+	}
+
+	expected := []string{"req-123", ""}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("received request ids %v, expected %v", received, expected)
+	}
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	var observed time.Duration
+	var observedErr error
+	mw := TimingMiddleware(func(req *http.Request, resp *http.Response, err error, d time.Duration) {
+		observed = d
+		observedErr = err
+	})
+
+	expectedErr := fmt.Errorf("boom")
+	rt := mw(func(req *http.Request) (*http.Response, error) {
+		time.Sleep(5 * time.Millisecond)
+		return nil, expectedErr
+	})
+
+	req, _ := http.NewRequest("GET", "https://fooshop.myshopify.com/foo", nil)
+	if _, err := rt(req); err != expectedErr {
+		t.Errorf("round trip returned %v, expected %v", err, expectedErr)
+	}
+	if observed < 5*time.Millisecond || observedErr != expectedErr {
+		t.Errorf("TimingMiddleware observed %s and %v", observed, observedErr)
+	}
+}
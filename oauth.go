@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"time"
 )
 
 const shopifyChecksumHeader = "X-Shopify-Hmac-Sha256"
@@ -39,11 +40,72 @@ func (app App) AuthorizeUrl(shopName string, state string) (string, error) {
 	return shopUrl.String(), nil
 }
 
+// Returns a Shopify oauth authorization url requesting an online access token,
+// tied to the user logging in and expiring with their session.
+func (app App) PerUserAuthorizeUrl(shopName string, state string) (string, error) {
+	authUrl, err := app.AuthorizeUrl(shopName, state)
+	if err != nil {
+		return "", err
+	}
+	shopUrl, err := url.Parse(authUrl)
+	if err != nil {
+		return "", err
+	}
+	query := shopUrl.Query()
+	query.Set("grant_options[]", "per-user")
+	shopUrl.RawQuery = query.Encode()
+	return shopUrl.String(), nil
+}
+
+// AccessTokenResponse is the response of an access token request. The
+// ExpiresIn, AssociatedUserScope and AssociatedUser fields are only set for
+// online access tokens.
+type AccessTokenResponse struct {
+	AccessToken         string          `json:"access_token"`
+	Scope               string          `json:"scope"`
+	ExpiresIn           int             `json:"expires_in,omitempty"`
+	AssociatedUserScope string          `json:"associated_user_scope,omitempty"`
+	AssociatedUser      *AssociatedUser `json:"associated_user,omitempty"`
+}
+
+// AssociatedUser is the user an online access token was issued for
+type AssociatedUser struct {
+	Id            uint64 `json:"id"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	AccountOwner  bool   `json:"account_owner"`
+	Locale        string `json:"locale"`
+	Collaborator  bool   `json:"collaborator"`
+}
+
+// Online returns true for an online access token
+func (r *AccessTokenResponse) Online() bool {
+	return r.AssociatedUser != nil
+}
+
+// ExpiresAt returns the time an online access token expires relative to
+// issuedAt, the time it was requested, and the zero time for offline tokens.
+func (r *AccessTokenResponse) ExpiresAt(issuedAt time.Time) time.Time {
+	if r.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+	return issuedAt.Add(time.Duration(r.ExpiresIn) * time.Second)
+}
+
 func (app App) GetAccessToken(ctx context.Context, shopName string, code string) (string, error) {
-	type Token struct {
-		Token string `json:"access_token"`
+	token, err := app.GetAccessTokenResponse(ctx, shopName, code)
+	if err != nil {
+		return "", err
 	}
+	return token.AccessToken, nil
+}
 
+// GetAccessTokenResponse exchanges an authorization code for an access token
+// and returns the full response, including the associated user of online
+// access tokens.
+func (app App) GetAccessTokenResponse(ctx context.Context, shopName string, code string) (*AccessTokenResponse, error) {
 	data := struct {
 		ClientId     string `json:"client_id"`
 		ClientSecret string `json:"client_secret"`
@@ -54,6 +116,49 @@ func (app App) GetAccessToken(ctx context.Context, shopName string, code string)
 		Code:         code,
 	}
 
+	return app.requestAccessToken(ctx, shopName, data)
+}
+
+// AccessTokenType is the type of access token requested by
+// ExchangeSessionToken
+type AccessTokenType string
+
+const (
+	OnlineAccessToken  AccessTokenType = "urn:shopify:params:oauth:token-type:online-access-token"
+	OfflineAccessToken AccessTokenType = "urn:shopify:params:oauth:token-type:offline-access-token"
+)
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	idTokenType            = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// ExchangeSessionToken exchanges the session token of an embedded app, see
+// VerifySessionToken, for an access token of tokenType. This is how apps
+// using Shopify managed installation get their access tokens, without going
+// through the authorization code grant.
+// See: https://shopify.dev/docs/apps/build/authentication-authorization/access-tokens/token-exchange
+func (app App) ExchangeSessionToken(ctx context.Context, shopName string, sessionToken string, tokenType AccessTokenType) (*AccessTokenResponse, error) {
+	data := struct {
+		ClientId           string          `json:"client_id"`
+		ClientSecret       string          `json:"client_secret"`
+		GrantType          string          `json:"grant_type"`
+		SubjectToken       string          `json:"subject_token"`
+		SubjectTokenType   string          `json:"subject_token_type"`
+		RequestedTokenType AccessTokenType `json:"requested_token_type"`
+	}{
+		ClientId:           app.ApiKey,
+		ClientSecret:       app.ApiSecret,
+		GrantType:          tokenExchangeGrantType,
+		SubjectToken:       sessionToken,
+		SubjectTokenType:   idTokenType,
+		RequestedTokenType: tokenType,
+	}
+
+	return app.requestAccessToken(ctx, shopName, data)
+}
+
+func (app App) requestAccessToken(ctx context.Context, shopName string, data interface{}) (*AccessTokenResponse, error) {
 	client := app.Client
 	if client == nil {
 		client = MustNewClient(app, shopName, "")
@@ -61,12 +166,15 @@ func (app App) GetAccessToken(ctx context.Context, shopName string, code string)
 
 	req, err := client.NewRequest(ctx, "POST", accessTokenRelPath, data, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	token := new(Token)
+	token := new(AccessTokenResponse)
 	err = client.Do(req, token)
-	return token.Token, err
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
 }
 
 // Verify a message against a message HMAC
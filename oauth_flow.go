@@ -0,0 +1,344 @@
+package goshopify
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOAuthStateCookie = "shopify_oauth_state"
+	defaultOAuthStateTTL    = 10 * time.Minute
+	defaultOAuthMaxAge      = 5 * time.Minute
+)
+
+// Errors of the OAuth callback validation, passed to OAuthHandler.OnError.
+var (
+	ErrInvalidShop  = errors.New("invalid shop domain")
+	ErrInvalidHMAC  = errors.New("invalid hmac")
+	ErrStaleRequest = errors.New("request timestamp too old")
+	ErrInvalidState = errors.New("invalid oauth state")
+)
+
+// NewOAuthState returns a cryptographically random state for an
+// authorization request.
+func NewOAuthState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// OAuthStateStore keeps the state of an authorization request between the
+// redirect to Shopify and the callback.
+type OAuthStateStore interface {
+	// Save records state for the browser sending r.
+	Save(w http.ResponseWriter, r *http.Request, state string) error
+
+	// Verify checks that state was saved for the browser sending r and
+	// consumes it, returning ErrInvalidState otherwise.
+	Verify(w http.ResponseWriter, r *http.Request, state string) error
+}
+
+// CookieStateStore is an OAuthStateStore keeping the state in a cookie
+// signed with a secret, so that it is bound to the browser that started the
+// install without any server side storage.
+type CookieStateStore struct {
+	secret []byte
+
+	// Name of the cookie, defaults to "shopify_oauth_state".
+	Name string
+
+	// TTL of the state, defaults to 10 minutes.
+	TTL time.Duration
+
+	// Insecure drops the Secure attribute of the cookie, for development
+	// over plain HTTP only.
+	Insecure bool
+}
+
+// NewCookieStateStore returns a CookieStateStore signing its cookie with
+// secret, e.g. the ApiSecret of the app.
+func NewCookieStateStore(secret []byte) *CookieStateStore {
+	return &CookieStateStore{secret: secret}
+}
+
+func (s *CookieStateStore) name() string {
+	if s.Name == "" {
+		return defaultOAuthStateCookie
+	}
+	return s.Name
+}
+
+func (s *CookieStateStore) sign(value string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Save sets the signed state cookie, see OAuthStateStore.
+func (s *CookieStateStore) Save(w http.ResponseWriter, r *http.Request, state string) error {
+	if len(s.secret) == 0 {
+		return errors.New("cookie state store has no secret")
+	}
+
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = defaultOAuthStateTTL
+	}
+
+	value := fmt.Sprintf("%s.%d", state, time.Now().Add(ttl).Unix())
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.name(),
+		Value:    value + "." + s.sign(value),
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   !s.Insecure,
+		// sent along with the top level redirect back from Shopify
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// Verify checks state against the signed cookie and clears it, see
+// OAuthStateStore.
+func (s *CookieStateStore) Verify(w http.ResponseWriter, r *http.Request, state string) error {
+	cookie, err := r.Cookie(s.name())
+	if err != nil {
+		return ErrInvalidState
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.name(),
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   !s.Insecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	i := strings.LastIndex(cookie.Value, ".")
+	if i < 0 {
+		return ErrInvalidState
+	}
+	value, sig := cookie.Value[:i], cookie.Value[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(value))) {
+		return ErrInvalidState
+	}
+
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 {
+		return ErrInvalidState
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return ErrInvalidState
+	}
+	if !hmac.Equal([]byte(parts[0]), []byte(state)) {
+		return ErrInvalidState
+	}
+
+	return nil
+}
+
+// MemoryStateStore is an OAuthStateStore keeping issued states in memory
+// until they are used or expire. It only works when the callback reaches the
+// process that started the install.
+type MemoryStateStore struct {
+	ttl time.Duration
+
+	mu     sync.Mutex
+	states map[string]time.Time
+}
+
+// NewMemoryStateStore returns a MemoryStateStore keeping states for ttl, or
+// 10 minutes if ttl is zero.
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	if ttl <= 0 {
+		ttl = defaultOAuthStateTTL
+	}
+	return &MemoryStateStore{ttl: ttl, states: make(map[string]time.Time)}
+}
+
+// Save records state, see OAuthStateStore.
+func (s *MemoryStateStore) Save(w http.ResponseWriter, r *http.Request, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for st, expires := range s.states {
+		if now.After(expires) {
+			delete(s.states, st)
+		}
+	}
+	s.states[state] = now.Add(s.ttl)
+	return nil
+}
+
+// Verify consumes state, see OAuthStateStore.
+func (s *MemoryStateStore) Verify(w http.ResponseWriter, r *http.Request, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires, ok := s.states[state]
+	delete(s.states, state)
+	if !ok || time.Now().After(expires) {
+		return ErrInvalidState
+	}
+	return nil
+}
+
+// OAuthTokenFunc receives the access token of a completed install. It
+// writes the response, e.g. a redirect into the app.
+type OAuthTokenFunc func(w http.ResponseWriter, r *http.Request, shop string, token string)
+
+// OAuthHandler implements the install flow of an app, see Begin and
+// Callback.
+//
+//	h := goshopify.NewOAuthHandler(app, goshopify.NewCookieStateStore([]byte(app.ApiSecret)),
+//		func(w http.ResponseWriter, r *http.Request, shop, token string) {
+//			// store the token
+//			http.Redirect(w, r, "/", http.StatusFound)
+//		})
+//	http.Handle("/auth", h.Begin())
+//	http.Handle("/auth/callback", h.Callback()) // the RedirectUrl of app
+type OAuthHandler struct {
+	app     App
+	store   OAuthStateStore
+	onToken OAuthTokenFunc
+
+	// MaxAge is the maximum age of the callback's timestamp, defaults to
+	// 5 minutes.
+	MaxAge time.Duration
+
+	// PerUser requests online access tokens, see App.PerUserAuthorizeUrl.
+	PerUser bool
+
+	// OnTokenResponse, if set, receives the full access token response
+	// instead of the OAuthTokenFunc, e.g. to keep the expiry and associated
+	// user of online access tokens.
+	OnTokenResponse func(w http.ResponseWriter, r *http.Request, shop string, token *AccessTokenResponse)
+
+	// OnError, if set, writes the response of a failed request instead of
+	// a plain 400, or 500 when the token request failed.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// NewOAuthHandler returns an OAuthHandler for app, keeping states in store
+// and passing access tokens to onToken. onToken may only be nil if
+// OnTokenResponse is set, else Callback fails every install.
+func NewOAuthHandler(app App, store OAuthStateStore, onToken OAuthTokenFunc) *OAuthHandler {
+	return &OAuthHandler{app: app, store: store, onToken: onToken}
+}
+
+// Begin returns the handler starting an install. It expects the shop's
+// myshopify.com domain in the shop query parameter and redirects to the
+// shop's authorization page with a new state.
+func (h *OAuthHandler) Begin() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shop := r.URL.Query().Get("shop")
+		if !ValidShopDomain(shop) {
+			h.fail(w, r, http.StatusBadRequest, fmt.Errorf("%w %q", ErrInvalidShop, shop))
+			return
+		}
+
+		state, err := NewOAuthState()
+		if err != nil {
+			h.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if err := h.store.Save(w, r, state); err != nil {
+			h.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		authorizeUrl := h.app.AuthorizeUrl
+		if h.PerUser {
+			authorizeUrl = h.app.PerUserAuthorizeUrl
+		}
+		authUrl, err := authorizeUrl(shop, state)
+		if err != nil {
+			h.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		http.Redirect(w, r, authUrl, http.StatusFound)
+	})
+}
+
+// Callback returns the handler of the redirect back from Shopify. It
+// validates the shop domain, HMAC, timestamp and state of the request, then
+// exchanges the authorization code for an access token and passes it to the
+// OAuthTokenFunc.
+func (h *OAuthHandler) Callback() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.onToken == nil && h.OnTokenResponse == nil {
+			h.fail(w, r, http.StatusInternalServerError, errors.New("oauth handler has no token handler"))
+			return
+		}
+
+		if err := h.verifyCallback(w, r); err != nil {
+			h.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		q := r.URL.Query()
+		shop := q.Get("shop")
+		token, err := h.app.GetAccessTokenResponse(r.Context(), shop, q.Get("code"))
+		if err != nil {
+			h.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if h.OnTokenResponse != nil {
+			h.OnTokenResponse(w, r, shop, token)
+			return
+		}
+		h.onToken(w, r, shop, token.AccessToken)
+	})
+}
+
+func (h *OAuthHandler) verifyCallback(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	if shop := q.Get("shop"); !ValidShopDomain(shop) {
+		return fmt.Errorf("%w %q", ErrInvalidShop, shop)
+	}
+
+	if ok, err := h.app.VerifyAuthorizationURL(r.URL); !ok || err != nil {
+		return ErrInvalidHMAC
+	}
+
+	maxAge := h.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultOAuthMaxAge
+	}
+	timestamp, err := strconv.ParseInt(q.Get("timestamp"), 10, 64)
+	if err != nil {
+		return ErrStaleRequest
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > maxAge || age < -maxAge {
+		return ErrStaleRequest
+	}
+
+	return h.store.Verify(w, r, q.Get("state"))
+}
+
+func (h *OAuthHandler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(w, r, err)
+		return
+	}
+	http.Error(w, http.StatusText(status), status)
+}
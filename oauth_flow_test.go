@@ -0,0 +1,254 @@
+package goshopify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// signedCallbackURL returns the callback url Shopify redirects to, signed
+// with the secret of app.
+func signedCallbackURL(t *testing.T, shop, state string, timestamp time.Time) string {
+	t.Helper()
+
+	q := url.Values{}
+	q.Set("code", "0907a61c0c8d55e99db179b68161bc00")
+	q.Set("shop", shop)
+	q.Set("state", state)
+	q.Set("timestamp", strconv.FormatInt(timestamp.Unix(), 10))
+
+	message, err := url.QueryUnescape(q.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, []byte(app.ApiSecret))
+	mac.Write([]byte(message))
+	q.Set("hmac", hex.EncodeToString(mac.Sum(nil)))
+
+	return "https://app.example.com/auth/callback?" + q.Encode()
+}
+
+// beginOAuth starts an install for fooshop and returns the state and the
+// cookies set by the begin handler.
+func beginOAuth(t *testing.T, h *OAuthHandler) (string, []*http.Cookie) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	h.Begin().ServeHTTP(w, httptest.NewRequest("GET", "https://app.example.com/auth?shop=fooshop.myshopify.com", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("OAuthHandler.Begin responded %d, expected %d", w.Code, http.StatusFound)
+	}
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.Host != "fooshop.myshopify.com" || location.Path != "/admin/oauth/authorize" {
+		t.Errorf("OAuthHandler.Begin redirected to %s", location)
+	}
+
+	state := location.Query().Get("state")
+	if len(state) < 32 {
+		t.Errorf("OAuthHandler.Begin generated a short state %q", state)
+	}
+	return state, w.Result().Cookies()
+}
+
+func TestOAuthHandler(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewStringResponder(200, `{"access_token":"footoken"}`))
+
+	tokenApp := app
+	tokenApp.Client = client
+
+	stores := map[string]OAuthStateStore{
+		"cookie": NewCookieStateStore([]byte(app.ApiSecret)),
+		"memory": NewMemoryStateStore(0),
+	}
+
+	for name, store := range stores {
+		var shop, token string
+		h := NewOAuthHandler(tokenApp, store, func(w http.ResponseWriter, r *http.Request, s, tk string) {
+			shop, token = s, tk
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		state, cookies := beginOAuth(t, h)
+
+		req := httptest.NewRequest("GET", signedCallbackURL(t, "fooshop.myshopify.com", state, time.Now()), nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		h.Callback().ServeHTTP(w, req)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("%s: OAuthHandler.Callback responded %d, expected %d", name, w.Code, http.StatusNoContent)
+		}
+		if shop != "fooshop.myshopify.com" || token != "footoken" {
+			t.Errorf("%s: OAuthHandler.Callback passed shop %q and token %q", name, shop, token)
+		}
+
+		// a state is only valid once
+		w = httptest.NewRecorder()
+		h.Callback().ServeHTTP(w, httptest.NewRequest("GET", signedCallbackURL(t, "fooshop.myshopify.com", state, time.Now()), nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: OAuthHandler.Callback responded %d to a reused state, expected %d", name, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestOAuthHandlerPerUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewBytesResponder(200, loadFixture("access_token_online.json")))
+
+	tokenApp := app
+	tokenApp.Client = client
+
+	h := NewOAuthHandler(tokenApp, NewMemoryStateStore(0), func(w http.ResponseWriter, r *http.Request, s, tk string) {
+		t.Error("OAuthHandler.Callback called the OAuthTokenFunc instead of OnTokenResponse")
+	})
+	h.PerUser = true
+
+	var token *AccessTokenResponse
+	h.OnTokenResponse = func(w http.ResponseWriter, r *http.Request, shop string, tk *AccessTokenResponse) {
+		token = tk
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	w := httptest.NewRecorder()
+	h.Begin().ServeHTTP(w, httptest.NewRequest("GET", "https://app.example.com/auth?shop=fooshop.myshopify.com", nil))
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grant := location.Query().Get("grant_options[]"); grant != "per-user" {
+		t.Errorf("OAuthHandler.Begin requested grant options %q, expected per-user", grant)
+	}
+
+	state := location.Query().Get("state")
+	w = httptest.NewRecorder()
+	h.Callback().ServeHTTP(w, httptest.NewRequest("GET", signedCallbackURL(t, "fooshop.myshopify.com", state, time.Now()), nil))
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("OAuthHandler.Callback responded %d, expected %d", w.Code, http.StatusNoContent)
+	}
+	if token == nil || !token.Online() || token.AssociatedUser.Id != 902541635 {
+		t.Errorf("OAuthHandler.Callback passed token %+v", token)
+	}
+}
+
+func TestOAuthHandlerWithoutTokenHandler(t *testing.T) {
+	setup()
+	defer teardown()
+
+	h := NewOAuthHandler(app, NewMemoryStateStore(0), nil)
+
+	var failure error
+	h.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		failure = err
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	state, _ := beginOAuth(t, h)
+
+	w := httptest.NewRecorder()
+	h.Callback().ServeHTTP(w, httptest.NewRequest("GET", signedCallbackURL(t, "fooshop.myshopify.com", state, time.Now()), nil))
+	if w.Code != http.StatusInternalServerError || failure == nil {
+		t.Errorf("OAuthHandler.Callback responded %d with error %v, expected %d", w.Code, failure, http.StatusInternalServerError)
+	}
+	if n := httpmock.GetTotalCallCount(); n != 0 {
+		t.Errorf("OAuthHandler.Callback requested an access token without a token handler")
+	}
+}
+
+func TestOAuthHandlerCallbackValidation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	h := NewOAuthHandler(app, NewMemoryStateStore(0), func(w http.ResponseWriter, r *http.Request, shop, token string) {
+		t.Errorf("OAuthHandler.Callback passed a token for an invalid request")
+	})
+
+	var lastErr error
+	h.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		lastErr = err
+		w.WriteHeader(http.StatusForbidden)
+	}
+
+	state, _ := beginOAuth(t, h)
+	tampered := signedCallbackURL(t, "fooshop.myshopify.com", state, time.Now()) + "&extra=1"
+
+	cases := []struct {
+		description string
+		url         string
+		expected    error
+	}{
+		{"invalid shop", signedCallbackURL(t, "evil.com", state, time.Now()), ErrInvalidShop},
+		{"invalid hmac", tampered, ErrInvalidHMAC},
+		{"stale timestamp", signedCallbackURL(t, "fooshop.myshopify.com", state, time.Now().Add(-time.Hour)), ErrStaleRequest},
+		{"unknown state", signedCallbackURL(t, "fooshop.myshopify.com", "unknown", time.Now()), ErrInvalidState},
+	}
+
+	for _, c := range cases {
+		lastErr = nil
+		w := httptest.NewRecorder()
+		h.Callback().ServeHTTP(w, httptest.NewRequest("GET", c.url, nil))
+		if w.Code != http.StatusForbidden || !errors.Is(lastErr, c.expected) {
+			t.Errorf("OAuthHandler.Callback %s responded %d with %v, expected %v", c.description, w.Code, lastErr, c.expected)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.Begin().ServeHTTP(w, httptest.NewRequest("GET", "https://app.example.com/auth?shop=evil.com", nil))
+	if !errors.Is(lastErr, ErrInvalidShop) {
+		t.Errorf("OAuthHandler.Begin returned %v for an invalid shop, expected %v", lastErr, ErrInvalidShop)
+	}
+}
+
+func TestCookieStateStoreTampered(t *testing.T) {
+	store := NewCookieStateStore([]byte("secret"))
+
+	w := httptest.NewRecorder()
+	if err := store.Save(w, httptest.NewRequest("GET", "/auth", nil), "state1"); err != nil {
+		t.Fatalf("CookieStateStore.Save returned %v", err)
+	}
+	cookie := w.Result().Cookies()[0]
+	if !cookie.HttpOnly || !cookie.Secure {
+		t.Errorf("CookieStateStore.Save set an insecure cookie %+v", cookie)
+	}
+
+	cases := []struct {
+		description string
+		store       *CookieStateStore
+		value       string
+		state       string
+	}{
+		{"other state", store, cookie.Value, "state2"},
+		{"forged cookie", store, "state2.9999999999.00", "state2"},
+		{"other secret", NewCookieStateStore([]byte("other")), cookie.Value, "state1"},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest("GET", "/auth/callback", nil)
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: c.value})
+		if err := c.store.Verify(httptest.NewRecorder(), req, c.state); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("CookieStateStore.Verify %s returned %v, expected %v", c.description, err, ErrInvalidState)
+		}
+	}
+}
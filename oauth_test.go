@@ -3,12 +3,15 @@ package goshopify
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 )
@@ -84,6 +87,7 @@ func TestAppGetAccessTokenError(t *testing.T) {
 	}
 
 	expectedError = errors.New("parse ://example.com: missing protocol scheme")
+	defer func(relPath string) { accessTokenRelPath = relPath }(accessTokenRelPath)
 	accessTokenRelPath = "://example.com" // cause NewRequest to trip a parse error
 	token, err = app.GetAccessToken(context.Background(), "fooshop", "")
 	if err == nil || !strings.Contains(err.Error(), "missing protocol scheme") {
@@ -274,3 +278,147 @@ func TestVerifyWebhookRequestVerbose(t *testing.T) {
 		t.Errorf("Expected error %s got %s", errors.New("test-error"), err)
 	}
 }
+
+func TestAppPerUserAuthorizeUrl(t *testing.T) {
+	setup()
+	defer teardown()
+
+	actual, err := app.PerUserAuthorizeUrl("fooshop", "thenonce")
+	if err != nil {
+		t.Fatalf("App.PerUserAuthorizeUrl(): %v", err)
+	}
+
+	expected := "https://fooshop.myshopify.com/admin/oauth/authorize?client_id=apikey&grant_options%5B%5D=per-user&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope=read_products&state=thenonce"
+	if actual != expected {
+		t.Errorf("App.PerUserAuthorizeUrl(): expected %s, actual %s", expected, actual)
+	}
+
+	if _, err := app.PerUserAuthorizeUrl("foo^^shop", "thenonce"); err == nil {
+		t.Error("App.PerUserAuthorizeUrl(): expected an error for an invalid shop name")
+	}
+}
+
+func TestAppGetAccessTokenResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewBytesResponder(200, loadFixture("access_token_online.json")))
+
+	app.Client = client
+	token, err := app.GetAccessTokenResponse(context.Background(), "fooshop", "foocode")
+	if err != nil {
+		t.Fatalf("App.GetAccessTokenResponse(): %v", err)
+	}
+
+	expected := &AccessTokenResponse{
+		AccessToken:         "f85632530bf277ec9ac6f649fc327f17",
+		Scope:               "write_orders",
+		ExpiresIn:           86399,
+		AssociatedUserScope: "write_orders",
+		AssociatedUser: &AssociatedUser{
+			Id:            902541635,
+			FirstName:     "John",
+			LastName:      "Smith",
+			Email:         "john@example.com",
+			EmailVerified: true,
+			AccountOwner:  true,
+			Locale:        "en",
+			Collaborator:  false,
+		},
+	}
+	if !reflect.DeepEqual(token, expected) {
+		t.Errorf("App.GetAccessTokenResponse() returned %+v, expected %+v", token, expected)
+	}
+
+	if !token.Online() {
+		t.Error("AccessTokenResponse.Online() = false, expected true")
+	}
+	issuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if expiresAt := token.ExpiresAt(issuedAt); !expiresAt.Equal(issuedAt.Add(86399 * time.Second)) {
+		t.Errorf("AccessTokenResponse.ExpiresAt() = %v", expiresAt)
+	}
+
+	offline := &AccessTokenResponse{AccessToken: "footoken"}
+	if offline.Online() || !offline.ExpiresAt(issuedAt).IsZero() {
+		t.Error("offline AccessTokenResponse reported as online")
+	}
+}
+
+func TestAppExchangeSessionToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var body map[string]string
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			return httpmock.NewBytesResponse(200, loadFixture("access_token_online.json")), nil
+		})
+
+	app.Client = client
+	token, err := app.ExchangeSessionToken(context.Background(), "fooshop", "thesessiontoken", OnlineAccessToken)
+	if err != nil {
+		t.Fatalf("App.ExchangeSessionToken(): %v", err)
+	}
+
+	expectedBody := map[string]string{
+		"client_id":            "apikey",
+		"client_secret":        "hush",
+		"grant_type":           "urn:ietf:params:oauth:grant-type:token-exchange",
+		"subject_token":        "thesessiontoken",
+		"subject_token_type":   "urn:ietf:params:oauth:token-type:id_token",
+		"requested_token_type": "urn:shopify:params:oauth:token-type:online-access-token",
+	}
+	if !reflect.DeepEqual(body, expectedBody) {
+		t.Errorf("App.ExchangeSessionToken() sent %+v, expected %+v", body, expectedBody)
+	}
+
+	if token.AccessToken != "f85632530bf277ec9ac6f649fc327f17" || token.ExpiresIn != 86399 || !token.Online() {
+		t.Errorf("App.ExchangeSessionToken() returned %+v", token)
+	}
+}
+
+func TestAppExchangeSessionTokenRedacted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewBytesResponder(200, loadFixture("access_token_online.json")))
+
+	logger := &recordingLogger{level: LevelDebug}
+	WithStructuredLogger(logger)(client)
+
+	app.Client = client
+	if _, err := app.ExchangeSessionToken(context.Background(), "fooshop", "thesessiontoken", OnlineAccessToken); err != nil {
+		t.Fatalf("App.ExchangeSessionToken(): %v", err)
+	}
+
+	if len(logger.records) != 1 {
+		t.Fatalf("structured logger received %d records, expected 1", len(logger.records))
+	}
+	body := logger.records[0].keyvals["request_body"].(string)
+	if strings.Contains(body, "thesessiontoken") || strings.Contains(body, "hush") || !strings.Contains(body, "subject_token_type") {
+		t.Errorf("structured record request_body = %s, expected a redacted body", body)
+	}
+	body = logger.records[0].keyvals["response_body"].(string)
+	if strings.Contains(body, "f85632530bf277ec9ac6f649fc327f17") {
+		t.Errorf("structured record response_body = %s, expected a redacted body", body)
+	}
+}
+
+func TestAppExchangeSessionTokenError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewStringResponder(400, `{"error":"invalid_subject_token","error_description":"Token is invalid"}`))
+
+	app.Client = client
+	token, err := app.ExchangeSessionToken(context.Background(), "fooshop", "expired", OfflineAccessToken)
+	if err == nil || token != nil {
+		t.Errorf("App.ExchangeSessionToken() returned %+v, %v, expected an error", token, err)
+	}
+}
@@ -21,11 +21,36 @@ func WithVersion(apiVersion string) Option {
 	}
 }
 
-// WithRetry sets the number of times a request will be retried if a rate limit or service unavailable error is returned.
-// Rate limiting can be either REST API limits or GraphQL Cost limits
+// WithRetry sets the number of times a request will be attempted if a rate limit or service unavailable error is returned.
+// Rate limiting can be either REST API limits or GraphQL Cost limits.
+// Use WithRetryPolicy for control over backoff and which errors are retried.
 func WithRetry(retries int) Option {
 	return func(c *Client) {
-		c.retries = retries
+		c.retryPolicy = RetryPolicy{
+			MaxAttempts:      retries,
+			RetryStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		}
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests, see
+// DefaultRetryPolicy for a sensible starting point.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter makes the client wait for room in the shop's REST and
+// GraphQL buckets before each call instead of running into 429 and THROTTLED
+// responses. Share one limiter between all clients of the same shop, e.g.
+//
+//	limiter := goshopify.NewLeakyBucketLimiter()
+//	c1 := goshopify.MustNewClient(app, "shopname", token, goshopify.WithRateLimiter(limiter))
+//	c2 := goshopify.MustNewClient(app, "shopname", token, goshopify.WithRateLimiter(limiter))
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
 	}
 }
 
@@ -35,6 +60,23 @@ func WithLogger(logger LeveledLoggerInterface) Option {
 	}
 }
 
+// WithStructuredLogger logs every API call as a structured record with the
+// method, path, status, duration and Shopify request id of the call. Headers
+// and bodies are added at debug level, redacted by the client's Redactor.
+func WithStructuredLogger(logger StructuredLogger) Option {
+	return func(c *Client) {
+		c.structuredLog = logger
+	}
+}
+
+// WithRedactor sets the Redactor applied to headers and bodies before they
+// are logged, defaults to DefaultRedactor. A nil Redactor logs them as is.
+func WithRedactor(redactor *Redactor) Option {
+	return func(c *Client) {
+		c.redactor = redactor
+	}
+}
+
 // WithHTTPClient is used to set a custom http client
 func WithHTTPClient(client *http.Client) Option {
 	return func(c *Client) {
@@ -42,6 +84,15 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
+// WithMiddleware adds middleware around every HTTP round trip of the client,
+// REST and GraphQL alike. Retries go through the middleware again. Middleware
+// added first sees the request first and the response last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
 func WithAdditionalHeaders(headers map[string]string) Option {
 	return func(c *Client) {
 		c.additionalHeaders = headers
@@ -57,3 +108,14 @@ func WithBaseUrl(baseUrl string) Option {
 		c.baseURL = baseUrl
 	}
 }
+
+// WithTokenSource sets the source of the access token of every request,
+// replacing the token passed to NewClient, so that rotated or re-issued
+// tokens are picked up without creating a new client. A request rejected
+// with a 401 is retried once with a new token if the source can invalidate
+// tokens, as CachingTokenSource does.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		c.tokenSource = ts
+	}
+}
@@ -42,8 +42,8 @@ func TestWithVersionInvalidVersion(t *testing.T) {
 func TestWithRetry(t *testing.T) {
 	c := MustNewClient(app, "fooshop", "abcd", WithRetry(5))
 	expected := 5
-	if c.retries != expected {
-		t.Errorf("WithRetry client.retries = %d, expected %d", c.retries, expected)
+	if c.retryPolicy.MaxAttempts != expected {
+		t.Errorf("WithRetry client.retryPolicy.MaxAttempts = %d, expected %d", c.retryPolicy.MaxAttempts, expected)
 	}
 }
 
@@ -594,6 +594,10 @@ type Transaction struct {
 	SourceName     string           `json:"source_name,omitempty"`
 	Source         string           `json:"source,omitempty"`
 	PaymentDetails *PaymentDetails  `json:"payment_details,omitempty"`
+
+	// MaximumRefundable is set on the suggested transactions of a refund
+	// calculation
+	MaximumRefundable *decimal.Decimal `json:"maximum_refundable,omitempty"`
 }
 
 type ClientDetails struct {
@@ -609,14 +613,27 @@ type Refund struct {
 	Id               uint64            `json:"id,omitempty"`
 	OrderId          uint64            `json:"order_id,omitempty"`
 	CreatedAt        *time.Time        `json:"created_at,omitempty"`
+	ProcessedAt      *time.Time        `json:"processed_at,omitempty"`
 	Note             string            `json:"note,omitempty"`
 	Restock          bool              `json:"restock,omitempty"`
+	Notify           bool              `json:"notify,omitempty"`
+	Currency         string            `json:"currency,omitempty"`
 	UserId           uint64            `json:"user_id,omitempty"`
+	Shipping         *RefundShipping   `json:"shipping,omitempty"`
 	RefundLineItems  []RefundLineItem  `json:"refund_line_items,omitempty"`
 	Transactions     []Transaction     `json:"transactions,omitempty"`
 	OrderAdjustments []OrderAdjustment `json:"order_adjustments,omitempty"`
 }
 
+// RefundShipping is the shipping refunded by a refund, either in full or a
+// specific amount.
+type RefundShipping struct {
+	FullRefund        bool             `json:"full_refund,omitempty"`
+	Amount            *decimal.Decimal `json:"amount,omitempty"`
+	Tax               *decimal.Decimal `json:"tax,omitempty"`
+	MaximumRefundable *decimal.Decimal `json:"maximum_refundable,omitempty"`
+}
+
 type OrderAdjustment struct {
 	Id           uint64              `json:"id,omitempty"`
 	OrderId      uint64              `json:"order_id,omitempty"`
@@ -637,15 +654,36 @@ const (
 )
 
 type RefundLineItem struct {
-	Id          uint64           `json:"id,omitempty"`
-	Quantity    int              `json:"quantity,omitempty"`
-	LineItemId  uint64           `json:"line_item_id,omitempty"`
-	LineItem    *LineItem        `json:"line_item,omitempty"`
-	Subtotal    *decimal.Decimal `json:"subtotal,omitempty"`
-	TotalTax    *decimal.Decimal `json:"total_tax,omitempty"`
-	SubTotalSet *AmountSet       `json:"subtotal_set,omitempty"`
-	TotalTaxSet *AmountSet       `json:"total_tax_set,omitempty"`
-}
+	Id          uint64            `json:"id,omitempty"`
+	Quantity    int               `json:"quantity,omitempty"`
+	LineItemId  uint64            `json:"line_item_id,omitempty"`
+	LineItem    *LineItem         `json:"line_item,omitempty"`
+	RestockType RefundRestockType `json:"restock_type,omitempty"`
+	LocationId  uint64            `json:"location_id,omitempty"`
+	Subtotal    *decimal.Decimal  `json:"subtotal,omitempty"`
+	TotalTax    *decimal.Decimal  `json:"total_tax,omitempty"`
+	SubTotalSet *AmountSet        `json:"subtotal_set,omitempty"`
+	TotalTaxSet *AmountSet        `json:"total_tax_set,omitempty"`
+}
+
+// RefundRestockType is how the refunded quantity of a line item affects
+// inventory
+type RefundRestockType string
+
+const (
+	// RefundRestockTypeNoRestock leaves inventory unchanged
+	RefundRestockTypeNoRestock RefundRestockType = "no_restock"
+
+	// RefundRestockTypeCancel restocks items that were never fulfilled
+	RefundRestockTypeCancel RefundRestockType = "cancel"
+
+	// RefundRestockTypeReturn restocks items that were returned
+	RefundRestockTypeReturn RefundRestockType = "return"
+
+	// RefundRestockTypeLegacyRestock is set on refunds created with the
+	// deprecated restock flag
+	RefundRestockTypeLegacyRestock RefundRestockType = "legacy_restock"
+)
 
 // List orders
 func (s *OrderServiceOp) List(ctx context.Context, options interface{}) ([]Order, error) {
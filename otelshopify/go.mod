@@ -0,0 +1,26 @@
+module github.com/growave-io/go-shopify/v4/otelshopify
+
+go 1.22.0
+
+require (
+	github.com/growave-io/go-shopify/v4 v4.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.34.0
+	go.opentelemetry.io/otel/metric v1.34.0
+	go.opentelemetry.io/otel/sdk v1.34.0
+	go.opentelemetry.io/otel/sdk/metric v1.34.0
+	go.opentelemetry.io/otel/trace v1.34.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/go-querystring v1.0.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/shopspring/decimal v0.0.0-20200105231215-408a2507e114 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
+
+// go-shopify has no tagged release with the middleware API yet, build
+// against the checkout this module lives in until there is one.
+replace github.com/growave-io/go-shopify/v4 => ../
@@ -0,0 +1,340 @@
+// Package otelshopify instruments goshopify clients with OpenTelemetry
+// traces and metrics.
+//
+// It lives in its own module so that goshopify itself does not depend on
+// OpenTelemetry.
+//
+//	inst, err := otelshopify.New()
+//	if err != nil {
+//		// handle error
+//	}
+//	policy := goshopify.DefaultRetryPolicy()
+//	policy.OnRetry = inst.OnRetry
+//	client, err := goshopify.NewClient(app, "shopname", token,
+//		goshopify.WithMiddleware(inst.Middleware()),
+//		goshopify.WithRetryPolicy(policy))
+package otelshopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	goshopify "github.com/growave-io/go-shopify/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName is the instrumentation scope of the tracer and meter.
+	ScopeName = "github.com/growave-io/go-shopify/v4/otelshopify"
+
+	graphQLThrottledCode = "THROTTLED"
+)
+
+// Attribute keys set on spans and metrics.
+const (
+	AttrHTTPMethod         = attribute.Key("http.request.method")
+	AttrHTTPStatusCode     = attribute.Key("http.response.status_code")
+	AttrServerAddress      = attribute.Key("server.address")
+	AttrShop               = attribute.Key("shopify.shop")
+	AttrResource           = attribute.Key("shopify.resource")
+	AttrAPIVersion         = attribute.Key("shopify.api_version")
+	AttrRequestID          = attribute.Key("shopify.request_id")
+	AttrBucket             = attribute.Key("shopify.bucket")
+	AttrRetryAttempt       = attribute.Key("shopify.retry.attempt")
+	AttrGraphQLRequested   = attribute.Key("shopify.graphql.cost.requested")
+	AttrGraphQLActual      = attribute.Key("shopify.graphql.cost.actual")
+	AttrGraphQLAvailable   = attribute.Key("shopify.graphql.cost.available")
+	AttrGraphQLMaximum     = attribute.Key("shopify.graphql.cost.maximum")
+	AttrGraphQLRestoreRate = attribute.Key("shopify.graphql.cost.restore_rate")
+)
+
+const (
+	bucketREST    = "rest"
+	bucketGraphQL = "graphql"
+)
+
+var (
+	// matches the version prefix of API paths, e.g. /admin/api/2023-01/
+	apiPathPrefixRegex = regexp.MustCompile(`^/admin(/api/[^/]+)?/`)
+	idSegmentRegex     = regexp.MustCompile(`^[0-9]+(\.json)?$`)
+)
+
+// Instrumentation creates spans and records metrics for the calls of
+// goshopify clients. One Instrumentation can be shared by many clients.
+type Instrumentation struct {
+	tracer trace.Tracer
+
+	duration  metric.Float64Histogram
+	retries   metric.Int64Counter
+	throttled metric.Int64Counter
+	available metric.Float64Gauge
+}
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures an Instrumentation.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, defaults to the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider, defaults to the global one.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// New returns an Instrumentation using the global tracer and meter providers
+// unless overridden by opts.
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	inst := &Instrumentation{
+		tracer: cfg.tracerProvider.Tracer(ScopeName),
+	}
+
+	var err error
+	inst.duration, err = meter.Float64Histogram("shopify.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Shopify API round trips."))
+	if err != nil {
+		return nil, err
+	}
+
+	inst.retries, err = meter.Int64Counter("shopify.client.retries",
+		metric.WithUnit("{retry}"),
+		metric.WithDescription("Number of retried Shopify API calls."))
+	if err != nil {
+		return nil, err
+	}
+
+	inst.throttled, err = meter.Int64Counter("shopify.client.throttled",
+		metric.WithUnit("{response}"),
+		metric.WithDescription("Number of Shopify API calls rejected by rate limiting."))
+	if err != nil {
+		return nil, err
+	}
+
+	inst.available, err = meter.Float64Gauge("shopify.client.bucket.available",
+		metric.WithUnit("{point}"),
+		metric.WithDescription("Remaining capacity of the shop's REST or GraphQL rate limit bucket."))
+	if err != nil {
+		return nil, err
+	}
+
+	return inst, nil
+}
+
+// Middleware returns the goshopify.Middleware creating a client span for
+// every round trip and recording its metrics.
+func (i *Instrumentation) Middleware() goshopify.Middleware {
+	return func(next goshopify.RoundTripFunc) goshopify.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return i.roundTrip(next, req)
+		}
+	}
+}
+
+func (i *Instrumentation) roundTrip(next goshopify.RoundTripFunc, req *http.Request) (*http.Response, error) {
+	resource := Resource(req.URL.Path)
+	shop := req.URL.Hostname()
+
+	attrs := []attribute.KeyValue{
+		AttrHTTPMethod.String(req.Method),
+		AttrServerAddress.String(shop),
+		AttrShop.String(shop),
+		AttrResource.String(resource),
+	}
+	if version := apiVersionFromPath(req.URL.Path); version != "" {
+		attrs = append(attrs, AttrAPIVersion.String(version))
+	}
+
+	ctx, span := i.tracer.Start(req.Context(), fmt.Sprintf("shopify %s %s", req.Method, resource),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	resp, err := next(req.WithContext(ctx))
+	elapsed := time.Since(start)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		i.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+		return resp, err
+	}
+
+	attrs = append(attrs, AttrHTTPStatusCode.Int(resp.StatusCode))
+	span.SetAttributes(AttrHTTPStatusCode.Int(resp.StatusCode))
+	if version := resp.Header.Get("X-Shopify-API-Version"); version != "" {
+		span.SetAttributes(AttrAPIVersion.String(version))
+	}
+	if id := resp.Header.Get("X-Request-Id"); id != "" {
+		span.SetAttributes(AttrRequestID.String(id))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	i.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+
+	bucketAttrs := metric.WithAttributes(AttrShop.String(shop), AttrBucket.String(bucketREST))
+	if resp.StatusCode == http.StatusTooManyRequests {
+		i.throttled.Add(ctx, 1, bucketAttrs)
+	}
+
+	if used, size, ok := parseCallLimit(resp.Header.Get("X-Shopify-Shop-Api-Call-Limit")); ok {
+		i.available.Record(ctx, float64(size-used), bucketAttrs)
+	}
+
+	if strings.HasSuffix(req.URL.Path, "/graphql.json") {
+		i.observeGraphQL(ctx, span, resp, shop)
+	}
+
+	return resp, nil
+}
+
+// graphQLResponse holds the parts of a GraphQL response that are
+// instrumented.
+type graphQLResponse struct {
+	Errors []struct {
+		Extensions *struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+	Extensions *struct {
+		Cost goshopify.GraphQLCost `json:"cost"`
+	} `json:"extensions"`
+}
+
+func (i *Instrumentation) observeGraphQL(ctx context.Context, span trace.Span, resp *http.Response, shop string) {
+	if resp.Body == nil {
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	var gr graphQLResponse
+	if json.Unmarshal(body, &gr) != nil {
+		return
+	}
+
+	bucketAttrs := metric.WithAttributes(AttrShop.String(shop), AttrBucket.String(bucketGraphQL))
+	for _, e := range gr.Errors {
+		if e.Extensions != nil && e.Extensions.Code == graphQLThrottledCode {
+			i.throttled.Add(ctx, 1, bucketAttrs)
+			break
+		}
+	}
+
+	if gr.Extensions == nil {
+		return
+	}
+
+	cost := gr.Extensions.Cost
+	span.SetAttributes(
+		AttrGraphQLRequested.Int(cost.RequestedQueryCost),
+		AttrGraphQLAvailable.Float64(cost.ThrottleStatus.CurrentlyAvailable),
+		AttrGraphQLMaximum.Float64(cost.ThrottleStatus.MaximumAvailable),
+		AttrGraphQLRestoreRate.Float64(cost.ThrottleStatus.RestoreRate),
+	)
+	if cost.ActualQueryCost != nil {
+		span.SetAttributes(AttrGraphQLActual.Int(*cost.ActualQueryCost))
+	}
+
+	i.available.Record(ctx, cost.ThrottleStatus.CurrentlyAvailable, bucketAttrs)
+}
+
+// OnRetry records a retry, use it as goshopify.RetryPolicy.OnRetry.
+func (i *Instrumentation) OnRetry(info goshopify.RetryInfo) {
+	ctx := context.Background()
+	attrs := []attribute.KeyValue{AttrRetryAttempt.Int(info.Attempt)}
+	if info.Request != nil {
+		ctx = info.Request.Context()
+		attrs = append(attrs,
+			AttrHTTPMethod.String(info.Request.Method),
+			AttrShop.String(info.Request.URL.Hostname()),
+			AttrResource.String(Resource(info.Request.URL.Path)))
+	}
+	if info.StatusCode != 0 {
+		attrs = append(attrs, AttrHTTPStatusCode.Int(info.StatusCode))
+	}
+
+	i.retries.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// Resource returns the low cardinality resource of an API path, with the
+// API prefix removed and ids replaced by placeholders, e.g.
+// "/admin/api/2023-01/orders/450789469/transactions.json" becomes
+// "orders/{id}/transactions".
+func Resource(path string) string {
+	path = apiPathPrefixRegex.ReplaceAllString(path, "")
+
+	segments := strings.Split(path, "/")
+	for n, segment := range segments {
+		if idSegmentRegex.MatchString(segment) {
+			segments[n] = "{id}"
+		}
+	}
+
+	return strings.TrimSuffix(strings.Join(segments, "/"), ".json")
+}
+
+func apiVersionFromPath(path string) string {
+	m := apiPathPrefixRegex.FindStringSubmatch(path)
+	if len(m) < 2 || m[1] == "" {
+		return ""
+	}
+	return strings.TrimPrefix(m[1], "/api/")
+}
+
+func parseCallLimit(header string) (used, size int, ok bool) {
+	s := strings.Split(header, "/")
+	if len(s) != 2 {
+		return 0, 0, false
+	}
+
+	used, err := strconv.Atoi(s[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	size, err = strconv.Atoi(s[1])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return used, size, true
+}
@@ -0,0 +1,229 @@
+package otelshopify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	goshopify "github.com/growave-io/go-shopify/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testApiVersion = "2023-01"
+
+type testEnv struct {
+	client *goshopify.Client
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+}
+
+func newTestEnv(t *testing.T, handler http.HandlerFunc) *testEnv {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	inst, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatalf("New() returned %v", err)
+	}
+
+	policy := goshopify.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxAttempts = 2
+	policy.OnRetry = inst.OnRetry
+
+	client := goshopify.MustNewClient(goshopify.App{}, "fooshop", "token",
+		goshopify.WithBaseUrl(srv.URL),
+		goshopify.WithVersion(testApiVersion),
+		goshopify.WithMiddleware(inst.Middleware()),
+		goshopify.WithRetryPolicy(policy))
+
+	return &testEnv{client: client, spans: spans, reader: reader}
+}
+
+func (e *testEnv) metrics(t *testing.T) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := e.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() returned %v", err)
+	}
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddlewareREST(t *testing.T) {
+	env := newTestEnv(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Header().Set("X-Shopify-API-Version", testApiVersion)
+		w.Header().Set("X-Shopify-Shop-Api-Call-Limit", "3/40")
+		w.Write([]byte(`{"transactions":[]}`))
+	})
+
+	if _, err := env.client.Transaction.List(context.Background(), 450789469, nil); err != nil {
+		t.Fatalf("Transaction.List() returned %v", err)
+	}
+
+	spans := env.spans.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, expected 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "shopify GET orders/{id}/transactions" {
+		t.Errorf("span name %q", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span kind %v, expected client", span.SpanKind())
+	}
+
+	expected := map[attribute.Key]attribute.Value{
+		AttrHTTPMethod:     attribute.StringValue("GET"),
+		AttrHTTPStatusCode: attribute.IntValue(200),
+		AttrResource:       attribute.StringValue("orders/{id}/transactions"),
+		AttrAPIVersion:     attribute.StringValue(testApiVersion),
+		AttrRequestID:      attribute.StringValue("req-1"),
+		AttrShop:           attribute.StringValue("127.0.0.1"),
+	}
+	for k, v := range expected {
+		if actual := spanAttr(span, k); actual != v {
+			t.Errorf("span attribute %s = %v, expected %v", k, actual.Emit(), v.Emit())
+		}
+	}
+
+	metrics := env.metrics(t)
+
+	duration, ok := metrics["shopify.client.request.duration"].(metricdata.Histogram[float64])
+	if !ok || len(duration.DataPoints) != 1 || duration.DataPoints[0].Count != 1 {
+		t.Errorf("request duration %+v, expected one observation", metrics["shopify.client.request.duration"])
+	}
+
+	available, ok := metrics["shopify.client.bucket.available"].(metricdata.Gauge[float64])
+	if !ok || len(available.DataPoints) != 1 || available.DataPoints[0].Value != 37 {
+		t.Errorf("bucket available %+v, expected 37", metrics["shopify.client.bucket.available"])
+	}
+}
+
+func TestMiddlewareRetriesAndThrottling(t *testing.T) {
+	env := newTestEnv(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0.001")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"errors":"Exceeded 2 calls per second for api client."}`))
+	})
+
+	if _, err := env.client.Shop.Get(context.Background(), nil); err == nil {
+		t.Fatal("Shop.Get() expected an error")
+	}
+
+	spans := env.spans.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, expected 2", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("span status %v, expected error", spans[0].Status())
+	}
+
+	metrics := env.metrics(t)
+
+	retries, ok := metrics["shopify.client.retries"].(metricdata.Sum[int64])
+	if !ok || len(retries.DataPoints) != 1 || retries.DataPoints[0].Value != 1 {
+		t.Errorf("retries %+v, expected 1", metrics["shopify.client.retries"])
+	}
+
+	throttled, ok := metrics["shopify.client.throttled"].(metricdata.Sum[int64])
+	if !ok || len(throttled.DataPoints) != 1 || throttled.DataPoints[0].Value != 2 {
+		t.Errorf("throttled %+v, expected 2", metrics["shopify.client.throttled"])
+	}
+}
+
+func TestMiddlewareGraphQL(t *testing.T) {
+	env := newTestEnv(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {"shop": {"name": "foo"}},
+			"extensions": {"cost": {
+				"requestedQueryCost": 12,
+				"actualQueryCost": 10,
+				"throttleStatus": {"maximumAvailable": 1000, "currentlyAvailable": 990, "restoreRate": 50}
+			}}
+		}`))
+	})
+
+	resp := struct {
+		Shop struct {
+			Name string `json:"name"`
+		} `json:"shop"`
+	}{}
+	if err := env.client.GraphQL.Query(context.Background(), "{ shop { name } }", nil, &resp); err != nil {
+		t.Fatalf("GraphQL.Query() returned %v", err)
+	}
+
+	// the middleware must leave the body readable for the client
+	if resp.Shop.Name != "foo" {
+		t.Errorf("GraphQL.Query() decoded %+v", resp)
+	}
+
+	span := env.spans.Ended()[0]
+	expected := map[attribute.Key]attribute.Value{
+		AttrResource:         attribute.StringValue("graphql"),
+		AttrGraphQLRequested: attribute.IntValue(12),
+		AttrGraphQLActual:    attribute.IntValue(10),
+		AttrGraphQLAvailable: attribute.Float64Value(990),
+	}
+	for k, v := range expected {
+		if actual := spanAttr(span, k); actual != v {
+			t.Errorf("span attribute %s = %v, expected %v", k, actual.Emit(), v.Emit())
+		}
+	}
+
+	available, ok := env.metrics(t)["shopify.client.bucket.available"].(metricdata.Gauge[float64])
+	if !ok || len(available.DataPoints) != 1 || available.DataPoints[0].Value != 990 {
+		t.Errorf("bucket available %+v, expected 990", available)
+	}
+}
+
+func TestResource(t *testing.T) {
+	cases := map[string]string{
+		"/admin/api/2023-01/products.json":                      "products",
+		"/admin/api/2023-01/products/123.json":                  "products/{id}",
+		"/admin/orders/450789469/transactions/389404469.json":   "orders/{id}/transactions/{id}",
+		"/admin/api/unstable/shopify_payments/payouts.json":     "shopify_payments/payouts",
+		"/admin/oauth/access_token":                             "oauth/access_token",
+		"/admin/api/2023-01/products/123/metafields/count.json": "products/{id}/metafields/count",
+	}
+
+	for path, expected := range cases {
+		if actual := Resource(path); actual != expected {
+			t.Errorf("Resource(%s) = %s, expected %s", path, actual, expected)
+		}
+	}
+}
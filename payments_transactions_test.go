@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"testing"
 	"time"
@@ -79,6 +80,37 @@ func TestPaymentsTransactionsList(t *testing.T) {
 	}
 }
 
+func TestPaymentsTransactionsListProcessedAt(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var query url.Values
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/balance/transactions.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query()
+			return httpmock.NewStringResponse(200, `{"transactions": []}`), nil
+		})
+
+	_, err := client.PaymentsTransactions.List(context.Background(), PaymentsTransactionsListOptions{PayoutId: 623721858})
+	if err != nil {
+		t.Fatalf("PaymentsTransactions.List returned error: %v", err)
+	}
+	expected := url.Values{"payout_id": {"623721858"}}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("PaymentsTransactions.List sent query %v, expected %v", query, expected)
+	}
+
+	date := OnlyDate{time.Date(2022, 0o2, 0o3, 0, 0, 0, 0, time.UTC)}
+	_, err = client.PaymentsTransactions.List(context.Background(), PaymentsTransactionsListOptions{ProcessedAt: &date})
+	if err != nil {
+		t.Fatalf("PaymentsTransactions.List returned error: %v", err)
+	}
+	expected = url.Values{"processed_at": {`"2022-02-03"`}}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("PaymentsTransactions.List sent query %v, expected %v", query, expected)
+	}
+}
+
 func TestPaymentsTransactionsListIncorrectDate(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,102 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxOrdersPerRequest is the number of orders fetched by id in one request
+const maxOrdersPerRequest = 250
+
+// PayoutReconciliation joins a payout to the balance transactions paid out
+// with it and to the orders they originate from.
+type PayoutReconciliation struct {
+	Payout       *Payout
+	Transactions []ReconciledTransaction
+
+	// Net is the sum of the net amounts of the transactions, excluding the
+	// transaction of the payout itself. It equals the payout amount when the
+	// payout is fully accounted for.
+	Net decimal.Decimal
+}
+
+// ReconciledTransaction is a balance transaction of a payout with its order
+type ReconciledTransaction struct {
+	PaymentsTransactions
+
+	// Order is the order of the transaction, nil for transactions without
+	// an order, e.g. adjustments, or if the order was deleted.
+	Order *Order
+}
+
+// Balanced returns true if the transactions add up to the payout amount
+func (r *PayoutReconciliation) Balanced() bool {
+	return r.Payout != nil && r.Net.Equal(r.Payout.Amount)
+}
+
+// Reconcile fetches a payout, all balance transactions paid out with it and
+// the orders of these transactions.
+func (s *PayoutsServiceOp) Reconcile(ctx context.Context, payoutId uint64) (*PayoutReconciliation, error) {
+	payout, err := s.Get(ctx, payoutId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.client.PaymentsTransactions.ListAll(ctx, &PaymentsTransactionsListOptions{
+		PayoutId: payoutId,
+		Limit:    250,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation := &PayoutReconciliation{Payout: payout}
+
+	var orderIds []uint64
+	seen := make(map[uint64]bool)
+	for _, transaction := range transactions {
+		if uint64(transaction.PayoutId) != payoutId {
+			continue
+		}
+		if transaction.Type != PaymentsTransactionsPayout {
+			net, err := decimal.NewFromString(transaction.Net)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d: invalid net amount %q: %w", transaction.Id, transaction.Net, err)
+			}
+			reconciliation.Net = reconciliation.Net.Add(net)
+		}
+		if id := uint64(transaction.SourceOrderId); id != 0 && !seen[id] {
+			seen[id] = true
+			orderIds = append(orderIds, id)
+		}
+		reconciliation.Transactions = append(reconciliation.Transactions, ReconciledTransaction{PaymentsTransactions: transaction})
+	}
+
+	orders := make(map[uint64]*Order, len(orderIds))
+	for start := 0; start < len(orderIds); start += maxOrdersPerRequest {
+		end := start + maxOrdersPerRequest
+		if end > len(orderIds) {
+			end = len(orderIds)
+		}
+		list, err := s.client.Order.List(ctx, OrderListOptions{
+			ListOptions: ListOptions{Ids: orderIds[start:end], Limit: maxOrdersPerRequest},
+			Status:      OrderStatusAny,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			orders[list[i].Id] = &list[i]
+		}
+	}
+
+	for i := range reconciliation.Transactions {
+		if id := uint64(reconciliation.Transactions[i].SourceOrderId); id != 0 {
+			reconciliation.Transactions[i].Order = orders[id]
+		}
+	}
+
+	return reconciliation, nil
+}
@@ -0,0 +1,55 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+)
+
+func TestPayoutsReconcile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/payouts/623721858.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("payout.json")))
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shopify_payments/balance/transactions.json", client.pathPrefix),
+		map[string]string{"payout_id": "623721858", "limit": "250"},
+		httpmock.NewBytesResponder(200, loadFixture("payout_transactions.json")))
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders.json", client.pathPrefix),
+		map[string]string{"ids": "450789469", "limit": "250", "status": "any"},
+		httpmock.NewStringResponder(200, `{"orders": [{"id": 450789469, "name": "#1001"}]}`))
+
+	reconciliation, err := client.Payouts.Reconcile(context.Background(), 623721858)
+	if err != nil {
+		t.Fatalf("Payouts.Reconcile returned error: %v", err)
+	}
+
+	if reconciliation.Payout == nil || reconciliation.Payout.Id != 623721858 {
+		t.Errorf("PayoutReconciliation.Payout returned %+v", reconciliation.Payout)
+	}
+
+	if len(reconciliation.Transactions) != 4 {
+		t.Fatalf("PayoutReconciliation.Transactions got %d transactions, expected 4", len(reconciliation.Transactions))
+	}
+
+	charge := reconciliation.Transactions[0]
+	if charge.Order == nil || charge.Order.Name != "#1001" {
+		t.Errorf("PayoutReconciliation charge order returned %+v", charge.Order)
+	}
+	for _, transaction := range reconciliation.Transactions[1:] {
+		if transaction.Order != nil {
+			t.Errorf("PayoutReconciliation transaction %d has order %+v, expected none", transaction.Id, transaction.Order)
+		}
+	}
+
+	expectedNet := decimal.NewFromFloat(41.9)
+	if !reconciliation.Net.Equal(expectedNet) {
+		t.Errorf("PayoutReconciliation.Net returned %s, expected %s", reconciliation.Net, expectedNet)
+	}
+	if !reconciliation.Balanced() {
+		t.Error("PayoutReconciliation.Balanced() = false, expected true")
+	}
+}
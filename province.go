@@ -0,0 +1,69 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProvinceService is an interface for interfacing with the province endpoints
+// of the Shopify API. Provinces are created and deleted along with their
+// country, the API has no endpoints for it.
+// See: https://shopify.dev/docs/api/admin-rest/latest/resources/province
+type ProvinceService interface {
+	List(context.Context, uint64, interface{}) ([]Province, error)
+	Count(context.Context, uint64, interface{}) (int, error)
+	Get(context.Context, uint64, uint64, interface{}) (*Province, error)
+	Update(context.Context, uint64, Province) (*Province, error)
+}
+
+// ProvinceServiceOp handles communication with the province related methods
+// of the Shopify API.
+type ProvinceServiceOp struct {
+	client *Client
+}
+
+// Province is a province of a country, the same type as the provinces of a
+// shipping zone. Tax is the province's tax rate, e.g. 0.08 for 8%, applied
+// instead of or in addition to the country's rate depending on TaxType.
+type Province = ShippingProvince
+
+// ProvinceResource represents the result from the countries/X/provinces/Y.json endpoint
+type ProvinceResource struct {
+	Province *Province `json:"province"`
+}
+
+// ProvincesResource represents the result from the countries/X/provinces.json endpoint
+type ProvincesResource struct {
+	Provinces []Province `json:"provinces"`
+}
+
+// List provinces of a country
+func (s *ProvinceServiceOp) List(ctx context.Context, countryId uint64, options interface{}) ([]Province, error) {
+	path := fmt.Sprintf("%s/%d/provinces.json", countriesBasePath, countryId)
+	resource := new(ProvincesResource)
+	err := s.client.Get(ctx, path, resource, options)
+	return resource.Provinces, err
+}
+
+// Count provinces of a country
+func (s *ProvinceServiceOp) Count(ctx context.Context, countryId uint64, options interface{}) (int, error) {
+	path := fmt.Sprintf("%s/%d/provinces/count.json", countriesBasePath, countryId)
+	return s.client.Count(ctx, path, options)
+}
+
+// Get individual province
+func (s *ProvinceServiceOp) Get(ctx context.Context, countryId uint64, provinceId uint64, options interface{}) (*Province, error) {
+	path := fmt.Sprintf("%s/%d/provinces/%d.json", countriesBasePath, countryId, provinceId)
+	resource := new(ProvinceResource)
+	err := s.client.Get(ctx, path, resource, options)
+	return resource.Province, err
+}
+
+// Update an existing province, e.g. its tax rate
+func (s *ProvinceServiceOp) Update(ctx context.Context, countryId uint64, province Province) (*Province, error) {
+	path := fmt.Sprintf("%s/%d/provinces/%d.json", countriesBasePath, countryId, province.Id)
+	wrappedData := ProvinceResource{Province: &province}
+	resource := new(ProvinceResource)
+	err := s.client.Put(ctx, path, wrappedData, resource)
+	return resource.Province, err
+}
@@ -0,0 +1,266 @@
+package goshopify
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// Shopify's REST leaky bucket for standard plans
+	defaultRESTBucketSize = 40
+	defaultRESTLeakRate   = 2
+
+	// Shopify's GraphQL cost bucket for standard plans
+	defaultGraphQLMaximumAvailable = 1000
+	defaultGraphQLRestoreRate      = 50
+)
+
+// RateLimiter throttles calls to the Shopify API before they are sent, so
+// that callers wait instead of burning retries on 429 and THROTTLED
+// responses. Implementations must be safe for concurrent use, a single
+// RateLimiter can be shared by every Client talking to the same shops.
+// See WithRateLimiter.
+type RateLimiter interface {
+	// WaitREST blocks until a REST call to shop fits in its bucket, or ctx
+	// is done.
+	WaitREST(ctx context.Context, shop string) error
+
+	// ObserveREST syncs the bucket of shop with the
+	// X-Shopify-Shop-Api-Call-Limit header of a response.
+	ObserveREST(shop string, requestCount, bucketSize int)
+
+	// WaitGraphQL blocks until a GraphQL query to shop fits in its cost
+	// bucket, or ctx is done.
+	WaitGraphQL(ctx context.Context, shop string) error
+
+	// ObserveGraphQL syncs the cost bucket of shop with the cost extension of
+	// a GraphQL response.
+	ObserveGraphQL(shop string, cost GraphQLCost)
+}
+
+// LeakyBucketLimiter is a RateLimiter modelling Shopify's REST leaky bucket
+// and GraphQL cost bucket for each shop.
+//
+// Every call reserves room in the bucket up front and waits for the bucket to
+// drain when it is over capacity. Bucket sizes and rates start at the values
+// of the exported fields and follow the values reported by Shopify after the
+// first response, so Shopify Plus shops get their larger buckets
+// automatically.
+type LeakyBucketLimiter struct {
+	// RESTBucketSize is the initial size of the REST bucket, defaults to 40.
+	RESTBucketSize int
+	// RESTLeakRate is the number of REST calls leaking out of the bucket per
+	// second, defaults to 2.
+	RESTLeakRate float64
+	// GraphQLMaximumAvailable is the initial size of the GraphQL cost
+	// bucket, defaults to 1000.
+	GraphQLMaximumAvailable float64
+	// GraphQLRestoreRate is the number of cost points restored per second,
+	// defaults to 50.
+	GraphQLRestoreRate float64
+
+	mu    sync.Mutex
+	shops map[string]*shopBuckets
+
+	// now and sleep are replaced in tests
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+type shopBuckets struct {
+	rest    restBucket
+	graphQL graphQLBucket
+}
+
+type restBucket struct {
+	size     float64
+	leakRate float64
+	level    float64
+	updated  time.Time
+}
+
+type graphQLBucket struct {
+	maximum     float64
+	restoreRate float64
+	available   float64
+	// the cost of the last query is used as the estimate for the next one
+	lastCost float64
+	updated  time.Time
+}
+
+// NewLeakyBucketLimiter returns a LeakyBucketLimiter with the default bucket
+// sizes of a standard Shopify plan.
+func NewLeakyBucketLimiter() *LeakyBucketLimiter {
+	return &LeakyBucketLimiter{
+		RESTBucketSize:          defaultRESTBucketSize,
+		RESTLeakRate:            defaultRESTLeakRate,
+		GraphQLMaximumAvailable: defaultGraphQLMaximumAvailable,
+		GraphQLRestoreRate:      defaultGraphQLRestoreRate,
+	}
+}
+
+func (l *LeakyBucketLimiter) timeNow() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// buckets returns the buckets of shop, creating them if needed. l.mu must be
+// held.
+func (l *LeakyBucketLimiter) buckets(shop string) *shopBuckets {
+	if l.shops == nil {
+		l.shops = make(map[string]*shopBuckets)
+	}
+
+	b, ok := l.shops[shop]
+	if !ok {
+		now := l.timeNow()
+		b = &shopBuckets{
+			rest: restBucket{
+				size:     orDefault(float64(l.RESTBucketSize), defaultRESTBucketSize),
+				leakRate: orDefault(l.RESTLeakRate, defaultRESTLeakRate),
+				updated:  now,
+			},
+			graphQL: graphQLBucket{
+				maximum:     orDefault(l.GraphQLMaximumAvailable, defaultGraphQLMaximumAvailable),
+				restoreRate: orDefault(l.GraphQLRestoreRate, defaultGraphQLRestoreRate),
+				updated:     now,
+			},
+		}
+		b.graphQL.available = b.graphQL.maximum
+		l.shops[shop] = b
+	}
+
+	return b
+}
+
+func orDefault(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func (b *restBucket) leak(now time.Time) {
+	b.level = math.Max(0, b.level-now.Sub(b.updated).Seconds()*b.leakRate)
+	b.updated = now
+}
+
+func (b *graphQLBucket) restore(now time.Time) {
+	b.available = math.Min(b.maximum, b.available+now.Sub(b.updated).Seconds()*b.restoreRate)
+	b.updated = now
+}
+
+// WaitREST implements RateLimiter.
+func (l *LeakyBucketLimiter) WaitREST(ctx context.Context, shop string) error {
+	l.mu.Lock()
+	b := &l.buckets(shop).rest
+	b.leak(l.timeNow())
+	b.level++
+	wait := secondsToDuration((b.level - b.size) / b.leakRate)
+	l.mu.Unlock()
+
+	if err := l.wait(ctx, wait); err != nil {
+		l.mu.Lock()
+		b.level = math.Max(0, b.level-1)
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// ObserveREST implements RateLimiter.
+func (l *LeakyBucketLimiter) ObserveREST(shop string, requestCount, bucketSize int) {
+	if bucketSize <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := &l.buckets(shop).rest
+	b.leak(l.timeNow())
+	if size := float64(bucketSize); size != b.size {
+		// Shopify Plus shops have a larger bucket that also leaks faster
+		b.leakRate = b.leakRate * size / b.size
+		b.size = size
+	}
+	// Calls reserved by this limiter may not have reached Shopify yet, so
+	// only ever move towards the more conservative value.
+	b.level = math.Max(b.level, float64(requestCount))
+}
+
+// WaitGraphQL implements RateLimiter.
+func (l *LeakyBucketLimiter) WaitGraphQL(ctx context.Context, shop string) error {
+	l.mu.Lock()
+	b := &l.buckets(shop).graphQL
+	b.restore(l.timeNow())
+	cost := b.lastCost
+	b.available -= cost
+	wait := secondsToDuration(-b.available / b.restoreRate)
+	l.mu.Unlock()
+
+	if err := l.wait(ctx, wait); err != nil {
+		l.mu.Lock()
+		b.available = math.Min(b.maximum, b.available+cost)
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// ObserveGraphQL implements RateLimiter.
+func (l *LeakyBucketLimiter) ObserveGraphQL(shop string, cost GraphQLCost) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := &l.buckets(shop).graphQL
+	b.restore(l.timeNow())
+
+	status := cost.ThrottleStatus
+	if status.MaximumAvailable > 0 {
+		b.maximum = status.MaximumAvailable
+		// same as for REST, never trust a fuller bucket than the one we model
+		b.available = math.Min(b.available, status.CurrentlyAvailable)
+	}
+	if status.RestoreRate > 0 {
+		b.restoreRate = status.RestoreRate
+	}
+	b.lastCost = float64(cost.RequestedQueryCost)
+}
+
+func (l *LeakyBucketLimiter) wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	if l.sleep != nil {
+		return l.sleep(ctx, d)
+	}
+	return sleepContext(ctx, d)
+}
+
+func secondsToDuration(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// sleepContext pauses the current goroutine for d or until ctx is done,
+// whichever happens first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,261 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// newTestLimiter returns a limiter with a frozen clock that records the
+// durations it was asked to wait instead of sleeping.
+func newTestLimiter(waits *[]time.Duration) *LeakyBucketLimiter {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLeakyBucketLimiter()
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+	return l
+}
+
+func TestLeakyBucketLimiterWaitREST(t *testing.T) {
+	var waits []time.Duration
+	l := newTestLimiter(&waits)
+	l.RESTBucketSize = 2
+	l.RESTLeakRate = 1
+
+	for i := 0; i < 4; i++ {
+		if err := l.WaitREST(context.Background(), "fooshop.myshopify.com"); err != nil {
+			t.Fatalf("LeakyBucketLimiter.WaitREST() returned %v", err)
+		}
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second}
+	if !reflect.DeepEqual(waits, expected) {
+		t.Errorf("LeakyBucketLimiter.WaitREST() waited %v, expected %v", waits, expected)
+	}
+
+	// other shops have their own bucket
+	if err := l.WaitREST(context.Background(), "barshop.myshopify.com"); err != nil {
+		t.Fatalf("LeakyBucketLimiter.WaitREST() returned %v", err)
+	}
+	if len(waits) != 2 {
+		t.Errorf("LeakyBucketLimiter.WaitREST() waited for another shop's bucket: %v", waits)
+	}
+}
+
+func TestLeakyBucketLimiterObserveREST(t *testing.T) {
+	var waits []time.Duration
+	l := newTestLimiter(&waits)
+
+	shop := "fooshop.myshopify.com"
+	l.ObserveREST(shop, 40, 40)
+	if err := l.WaitREST(context.Background(), shop); err != nil {
+		t.Fatalf("LeakyBucketLimiter.WaitREST() returned %v", err)
+	}
+
+	expected := []time.Duration{500 * time.Millisecond}
+	if !reflect.DeepEqual(waits, expected) {
+		t.Errorf("LeakyBucketLimiter.WaitREST() waited %v, expected %v", waits, expected)
+	}
+
+	// a Shopify Plus bucket is larger and leaks faster
+	l.ObserveREST(shop, 41, 400)
+	b := l.shops[shop].rest
+	if b.size != 400 || b.leakRate != 20 || b.level != 41 {
+		t.Errorf("LeakyBucketLimiter.ObserveREST() bucket %+v, expected size 400, leak rate 20, level 41", b)
+	}
+
+	// a missing header does not reset the bucket
+	l.ObserveREST(shop, 0, 0)
+	if l.shops[shop].rest.size != 400 {
+		t.Errorf("LeakyBucketLimiter.ObserveREST() reset the bucket size to %v", l.shops[shop].rest.size)
+	}
+}
+
+func TestLeakyBucketLimiterGraphQL(t *testing.T) {
+	var waits []time.Duration
+	l := newTestLimiter(&waits)
+
+	shop := "fooshop.myshopify.com"
+	l.ObserveGraphQL(shop, GraphQLCost{
+		RequestedQueryCost: 600,
+		ThrottleStatus: GraphQLThrottleStatus{
+			MaximumAvailable:   1000,
+			CurrentlyAvailable: 1000,
+			RestoreRate:        50,
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := l.WaitGraphQL(context.Background(), shop); err != nil {
+			t.Fatalf("LeakyBucketLimiter.WaitGraphQL() returned %v", err)
+		}
+	}
+
+	expected := []time.Duration{4 * time.Second}
+	if !reflect.DeepEqual(waits, expected) {
+		t.Errorf("LeakyBucketLimiter.WaitGraphQL() waited %v, expected %v", waits, expected)
+	}
+}
+
+func TestLeakyBucketLimiterContextCanceled(t *testing.T) {
+	var waits []time.Duration
+	l := newTestLimiter(&waits)
+	l.RESTBucketSize = 1
+
+	shop := "fooshop.myshopify.com"
+	if err := l.WaitREST(context.Background(), shop); err != nil {
+		t.Fatalf("LeakyBucketLimiter.WaitREST() returned %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.WaitREST(ctx, shop); err != context.Canceled {
+		t.Errorf("LeakyBucketLimiter.WaitREST() returned %v, expected %v", err, context.Canceled)
+	}
+
+	// the canceled call gives its reservation back
+	if level := l.shops[shop].rest.level; level != 1 {
+		t.Errorf("LeakyBucketLimiter bucket level %v, expected 1", level)
+	}
+}
+
+func TestLeakyBucketLimiterSleeps(t *testing.T) {
+	l := NewLeakyBucketLimiter()
+	l.RESTBucketSize = 1
+	l.RESTLeakRate = 1000
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.WaitREST(context.Background(), "fooshop.myshopify.com"); err != nil {
+			t.Fatalf("LeakyBucketLimiter.WaitREST() returned %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Millisecond {
+		t.Errorf("LeakyBucketLimiter.WaitREST() returned after %s, expected at least 1ms", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	l.RESTLeakRate = 0.001
+	l.shops = nil
+	_ = l.WaitREST(ctx, "fooshop.myshopify.com")
+	if err := l.WaitREST(ctx, "fooshop.myshopify.com"); err != context.DeadlineExceeded {
+		t.Errorf("LeakyBucketLimiter.WaitREST() returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLeakyBucketLimiterShared(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shop.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"shop":{"id":1}}`))
+
+	limiter := NewLeakyBucketLimiter()
+	limiter.RESTLeakRate = 1e6
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		c := MustNewClient(app, "fooshop", "abcd", WithVersion(testApiVersion), WithRateLimiter(limiter), WithHTTPClient(client.Client))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Shop.Get(context.Background(), nil); err != nil {
+				t.Errorf("Shop.Get() returned %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := httpmock.GetTotalCallCount(); n != 20 {
+		t.Errorf("made %d calls, expected 20", n)
+	}
+}
+
+type recordingLimiter struct {
+	calls []string
+}
+
+func (l *recordingLimiter) WaitREST(ctx context.Context, shop string) error {
+	l.calls = append(l.calls, "WaitREST "+shop)
+	return nil
+}
+
+func (l *recordingLimiter) ObserveREST(shop string, requestCount, bucketSize int) {
+	l.calls = append(l.calls, fmt.Sprintf("ObserveREST %s %d/%d", shop, requestCount, bucketSize))
+}
+
+func (l *recordingLimiter) WaitGraphQL(ctx context.Context, shop string) error {
+	l.calls = append(l.calls, "WaitGraphQL "+shop)
+	return nil
+}
+
+func (l *recordingLimiter) ObserveGraphQL(shop string, cost GraphQLCost) {
+	l.calls = append(l.calls, fmt.Sprintf("ObserveGraphQL %s %d", shop, cost.RequestedQueryCost))
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	limiter := &recordingLimiter{}
+	WithRateLimiter(limiter)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shop.json", client.pathPrefix),
+		createResponderWithHeaders(200, `{"shop":{"id":1}}`, map[string]string{"X-Shopify-Shop-Api-Call-Limit": "5/40"}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/graphql.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"data":{},"extensions":{"cost":{"requestedQueryCost":12,"throttleStatus":{"maximumAvailable":1000,"currentlyAvailable":988,"restoreRate":50}}}}`))
+
+	if _, err := client.Shop.Get(context.Background(), nil); err != nil {
+		t.Fatalf("Shop.Get() returned %v", err)
+	}
+	if err := client.GraphQL.Query(context.Background(), "query {}", nil, nil); err != nil {
+		t.Fatalf("GraphQL.Query() returned %v", err)
+	}
+
+	expected := []string{
+		"WaitREST fooshop.myshopify.com",
+		"ObserveREST fooshop.myshopify.com 5/40",
+		"WaitGraphQL fooshop.myshopify.com",
+		"ObserveGraphQL fooshop.myshopify.com 12",
+	}
+	if !reflect.DeepEqual(limiter.calls, expected) {
+		t.Errorf("limiter calls %v, expected %v", limiter.calls, expected)
+	}
+}
+
+func TestWithRateLimiterWaitError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	limiter := NewLeakyBucketLimiter()
+	limiter.RESTBucketSize = 1
+	limiter.RESTLeakRate = 0.001
+	WithRateLimiter(limiter)(client)
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/shop.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"shop":{"id":1}}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Shop.Get(ctx, nil); err != nil {
+		t.Fatalf("Shop.Get() returned %v", err)
+	}
+	if _, err := client.Shop.Get(ctx, nil); err != context.DeadlineExceeded {
+		t.Errorf("Shop.Get() returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+	if n := httpmock.GetTotalCallCount(); n != 1 {
+		t.Errorf("made %d calls, expected 1", n)
+	}
+}
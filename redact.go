@@ -0,0 +1,125 @@
+package goshopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const defaultRedactionMask = "[REDACTED]"
+
+// Redactor masks secrets and personal data in headers and JSON bodies before
+// they are logged. See WithRedactor.
+type Redactor struct {
+	// Headers whose values are masked, matched case insensitively.
+	Headers []string
+
+	// Fields are JSON object keys whose values are masked at any depth,
+	// matched case insensitively.
+	Fields []string
+
+	// Mask replaces redacted values, defaults to "[REDACTED]".
+	Mask string
+}
+
+// DefaultRedactor returns a Redactor masking credentials as well as the
+// email, phone and name fields of customers and orders and their addresses
+// as a whole.
+func DefaultRedactor() *Redactor {
+	return &Redactor{
+		Headers: []string{
+			"X-Shopify-Access-Token",
+			"Authorization",
+			"Cookie",
+			"Set-Cookie",
+		},
+		Fields: []string{
+			"access_token",
+			"client_secret",
+			"subject_token",
+			"password",
+			"email",
+			"contact_email",
+			"phone",
+			"first_name",
+			"last_name",
+			"company",
+			"address1",
+			"address2",
+			"zip",
+			"latitude",
+			"longitude",
+			"addresses",
+			"default_address",
+			"billing_address",
+			"shipping_address",
+			"customer_address",
+		},
+		Mask: defaultRedactionMask,
+	}
+}
+
+func (r *Redactor) mask() string {
+	if r.Mask == "" {
+		return defaultRedactionMask
+	}
+	return r.Mask
+}
+
+// RedactHeader returns a copy of h with the values of sensitive headers
+// masked.
+func (r *Redactor) RedactHeader(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for k, v := range h {
+		redacted[k] = v
+		for _, name := range r.Headers {
+			if strings.EqualFold(k, name) {
+				redacted[k] = []string{r.mask()}
+				break
+			}
+		}
+	}
+	return redacted
+}
+
+// RedactBody returns body with the values of sensitive fields masked. Bodies
+// that are not JSON are returned unchanged.
+func (r *Redactor) RedactBody(body []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+
+	redacted, err := json.Marshal(r.redactValue(v))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			if elem != nil && r.isSensitiveField(k) {
+				v[k] = r.mask()
+				continue
+			}
+			v[k] = r.redactValue(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = r.redactValue(elem)
+		}
+	}
+	return v
+}
+
+func (r *Redactor) isSensitiveField(key string) bool {
+	for _, f := range r.Fields {
+		if strings.EqualFold(key, f) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package goshopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRedactorRedactHeader(t *testing.T) {
+	h := http.Header{
+		"X-Shopify-Access-Token": {"shpat_secret"},
+		"Content-Type":           {"application/json"},
+	}
+
+	redacted := DefaultRedactor().RedactHeader(h)
+
+	expected := http.Header{
+		"X-Shopify-Access-Token": {"[REDACTED]"},
+		"Content-Type":           {"application/json"},
+	}
+	if !reflect.DeepEqual(redacted, expected) {
+		t.Errorf("Redactor.RedactHeader returned %+v, expected %+v", redacted, expected)
+	}
+	if h.Get("X-Shopify-Access-Token") != "shpat_secret" {
+		t.Errorf("Redactor.RedactHeader modified its input")
+	}
+}
+
+func TestRedactorRedactBody(t *testing.T) {
+	body := []byte(`{"customer":{"id":1,"email":"bob@example.com","addresses":[{"address1":"1 Rue des Carrieres","city":"Montreal"}]}}`)
+
+	redacted := DefaultRedactor().RedactBody(body)
+
+	var actual, expected interface{}
+	if err := json.Unmarshal(redacted, &actual); err != nil {
+		t.Fatalf("Redactor.RedactBody returned invalid JSON %s: %v", redacted, err)
+	}
+	json.Unmarshal([]byte(`{"customer":{"id":1,"email":"[REDACTED]","addresses":"[REDACTED]"}}`), &expected)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Redactor.RedactBody returned %s", redacted)
+	}
+
+	body = []byte(`{"order":{"id":2,"total_price":"10.00","shipping_address":{"name":"Bob Norman","address1":"1 Rue des Carrieres","city":"Montreal","province":"Quebec","province_code":"QC","zip":"H2X 1Y4","country_code":"CA"},"line_items":[{"name":"IPod Nano"}]}}`)
+
+	redacted = DefaultRedactor().RedactBody(body)
+
+	actual, expected = nil, nil
+	if err := json.Unmarshal(redacted, &actual); err != nil {
+		t.Fatalf("Redactor.RedactBody returned invalid JSON %s: %v", redacted, err)
+	}
+	json.Unmarshal([]byte(`{"order":{"id":2,"total_price":"10.00","shipping_address":"[REDACTED]","line_items":[{"name":"IPod Nano"}]}}`), &expected)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Redactor.RedactBody returned %s", redacted)
+	}
+
+	plain := []byte("not json")
+	if actual := DefaultRedactor().RedactBody(plain); string(actual) != "not json" {
+		t.Errorf("Redactor.RedactBody returned %s, expected %s", actual, plain)
+	}
+}
+
+func TestRedactorMask(t *testing.T) {
+	r := &Redactor{Fields: []string{"token"}, Mask: "***"}
+
+	actual := r.RedactBody([]byte(`{"Token":"abc","other":null}`))
+	expected := `{"Token":"***","other":null}`
+	if string(actual) != expected {
+		t.Errorf("Redactor.RedactBody returned %s, expected %s", actual, expected)
+	}
+}
@@ -0,0 +1,91 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+)
+
+// RefundService is an interface for interfacing with the refund endpoints of
+// the Shopify API.
+// See: https://shopify.dev/docs/api/admin-rest/latest/resources/refund
+type RefundService interface {
+	List(context.Context, uint64, interface{}) ([]Refund, error)
+	ListWithPagination(context.Context, uint64, interface{}) ([]Refund, *Pagination, error)
+	Get(context.Context, uint64, uint64, interface{}) (*Refund, error)
+	Calculate(context.Context, uint64, Refund) (*Refund, error)
+	Create(context.Context, uint64, Refund) (*Refund, error)
+}
+
+// RefundServiceOp handles communication with the refund related methods of
+// the Shopify API.
+type RefundServiceOp struct {
+	client *Client
+}
+
+// RefundResource represents the result from the orders/X/refunds/Y.json endpoint
+type RefundResource struct {
+	Refund *Refund `json:"refund"`
+}
+
+// RefundsResource represents the result from the orders/X/refunds.json endpoint
+type RefundsResource struct {
+	Refunds []Refund `json:"refunds"`
+}
+
+// RefundListOptions are the options of listing the refunds of an order
+type RefundListOptions struct {
+	ListOptions
+	InShopCurrency bool `url:"in_shop_currency,omitempty"`
+}
+
+// List refunds of an order
+func (s *RefundServiceOp) List(ctx context.Context, orderId uint64, options interface{}) ([]Refund, error) {
+	refunds, _, err := s.ListWithPagination(ctx, orderId, options)
+	if err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// ListWithPagination lists refunds of an order and returns pagination to
+// retrieve next/previous results.
+func (s *RefundServiceOp) ListWithPagination(ctx context.Context, orderId uint64, options interface{}) ([]Refund, *Pagination, error) {
+	path := fmt.Sprintf("%s/%d/refunds.json", ordersBasePath, orderId)
+	resource := new(RefundsResource)
+
+	pagination, err := s.client.ListWithPagination(ctx, path, resource, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource.Refunds, pagination, nil
+}
+
+// Get individual refund
+func (s *RefundServiceOp) Get(ctx context.Context, orderId uint64, refundId uint64, options interface{}) (*Refund, error) {
+	path := fmt.Sprintf("%s/%d/refunds/%d.json", ordersBasePath, orderId, refundId)
+	resource := new(RefundResource)
+	err := s.client.Get(ctx, path, resource, options)
+	return resource.Refund, err
+}
+
+// Calculate a refund without creating it. The returned refund holds the
+// refundable amounts of the line items and shipping, and suggested
+// transactions that can be passed to Create after changing their kind to
+// "refund".
+func (s *RefundServiceOp) Calculate(ctx context.Context, orderId uint64, refund Refund) (*Refund, error) {
+	path := fmt.Sprintf("%s/%d/refunds/calculate.json", ordersBasePath, orderId)
+	wrappedData := RefundResource{Refund: &refund}
+	resource := new(RefundResource)
+	err := s.client.Post(ctx, path, wrappedData, resource)
+	return resource.Refund, err
+}
+
+// Create a refund for an order
+func (s *RefundServiceOp) Create(ctx context.Context, orderId uint64, refund Refund) (*Refund, error) {
+	path := fmt.Sprintf("%s/%d/refunds.json", ordersBasePath, orderId)
+	wrappedData := RefundResource{Refund: &refund}
+	resource := new(RefundResource)
+	err := s.client.Post(ctx, path, wrappedData, resource)
+	return resource.Refund, err
+}
@@ -0,0 +1,188 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+)
+
+func refundTests(t *testing.T, refund *Refund) {
+	expectedId := uint64(509562969)
+	if refund.Id != expectedId {
+		t.Errorf("Refund.Id returned %+v, expected %+v", refund.Id, expectedId)
+	}
+
+	expectedOrderId := uint64(450789469)
+	if refund.OrderId != expectedOrderId {
+		t.Errorf("Refund.OrderId returned %+v, expected %+v", refund.OrderId, expectedOrderId)
+	}
+
+	if len(refund.RefundLineItems) != 1 {
+		t.Fatalf("Refund.RefundLineItems returned %d items, expected 1", len(refund.RefundLineItems))
+	}
+	item := refund.RefundLineItems[0]
+	if item.RestockType != RefundRestockTypeReturn {
+		t.Errorf("RefundLineItem.RestockType returned %+v, expected %+v", item.RestockType, RefundRestockTypeReturn)
+	}
+	expectedLocationId := uint64(487838322)
+	if item.LocationId != expectedLocationId {
+		t.Errorf("RefundLineItem.LocationId returned %+v, expected %+v", item.LocationId, expectedLocationId)
+	}
+	expectedSubtotal := decimal.NewFromFloat(195.66)
+	if item.Subtotal == nil || !item.Subtotal.Equals(expectedSubtotal) {
+		t.Errorf("RefundLineItem.Subtotal returned %+v, expected %+v", item.Subtotal, expectedSubtotal)
+	}
+}
+
+func TestRefundList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/450789469/refunds.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("refunds.json")))
+
+	refunds, err := client.Refund.List(context.Background(), 450789469, nil)
+	if err != nil {
+		t.Errorf("Refund.List returned error: %v", err)
+	}
+
+	if len(refunds) != 1 {
+		t.Fatalf("Refund.List got %v refunds, expected: 1", len(refunds))
+	}
+
+	refundTests(t, &refunds[0])
+}
+
+func TestRefundListWithPagination(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/450789469/refunds.json", client.pathPrefix)
+
+	httpmock.RegisterResponder("GET", listURL,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewBytesResponse(200, loadFixture("refunds.json"))
+			resp.Header.Set("Link", `<http://valid.url?page_info=pageInfoCode&limit=1>; rel="next"`)
+			return resp, nil
+		})
+
+	refunds, pagination, err := client.Refund.ListWithPagination(context.Background(), 450789469, &RefundListOptions{ListOptions: ListOptions{Limit: 1}})
+	if err != nil {
+		t.Fatalf("Refund.ListWithPagination returned error: %v", err)
+	}
+
+	if len(refunds) != 1 {
+		t.Errorf("Refund.ListWithPagination got %v refunds, expected: 1", len(refunds))
+	}
+
+	expectedPageInfo := &ListOptions{PageInfo: "pageInfoCode", Limit: 1}
+	if pagination.NextPageOptions == nil || !reflect.DeepEqual(pagination.NextPageOptions, expectedPageInfo) {
+		t.Errorf("Refund.ListWithPagination next page returned %+v, expected %+v", pagination.NextPageOptions, expectedPageInfo)
+	}
+}
+
+func TestRefundGet(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/450789469/refunds/509562969.json", client.pathPrefix),
+		httpmock.NewBytesResponder(200, loadFixture("refund.json")))
+
+	refund, err := client.Refund.Get(context.Background(), 450789469, 509562969, nil)
+	if err != nil {
+		t.Errorf("Refund.Get returned error: %v", err)
+	}
+
+	refundTests(t, refund)
+
+	if len(refund.OrderAdjustments) != 1 || refund.OrderAdjustments[0].Kind != OrderAdjustmentTypeShippingRefund {
+		t.Errorf("Refund.OrderAdjustments returned %+v", refund.OrderAdjustments)
+	}
+}
+
+func TestRefundCalculate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var body map[string]interface{}
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/450789469/refunds/calculate.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			return httpmock.NewBytesResponse(200, loadFixture("refund_calculate.json")), nil
+		})
+
+	refund, err := client.Refund.Calculate(context.Background(), 450789469, Refund{
+		Shipping: &RefundShipping{FullRefund: true},
+		RefundLineItems: []RefundLineItem{
+			{LineItemId: 518995019, Quantity: 1, RestockType: RefundRestockTypeReturn, LocationId: 24826418},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Refund.Calculate returned error: %v", err)
+	}
+
+	expectedBody := map[string]interface{}{
+		"refund": map[string]interface{}{
+			"shipping": map[string]interface{}{"full_refund": true},
+			"refund_line_items": []interface{}{
+				map[string]interface{}{
+					"line_item_id": float64(518995019),
+					"quantity":     float64(1),
+					"restock_type": "return",
+					"location_id":  float64(24826418),
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(body, expectedBody) {
+		t.Errorf("Refund.Calculate sent %+v, expected %+v", body, expectedBody)
+	}
+
+	expectedShipping := decimal.NewFromFloat(5)
+	if refund.Shipping == nil || !refund.Shipping.MaximumRefundable.Equals(expectedShipping) {
+		t.Errorf("Refund.Shipping returned %+v, expected a maximum refundable of %v", refund.Shipping, expectedShipping)
+	}
+
+	if len(refund.Transactions) != 1 {
+		t.Fatalf("Refund.Transactions returned %d transactions, expected 1", len(refund.Transactions))
+	}
+	transaction := refund.Transactions[0]
+	expectedMaximum := decimal.NewFromFloat(41.94)
+	if transaction.Kind != "suggested_refund" || !transaction.MaximumRefundable.Equals(expectedMaximum) {
+		t.Errorf("Refund.Transactions returned %+v", transaction)
+	}
+}
+
+func TestRefundCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/orders/450789469/refunds.json", client.pathPrefix),
+		httpmock.NewBytesResponder(201, loadFixture("refund.json")))
+
+	amount := decimal.NewFromFloat(41.94)
+	parentId := int64(801038806)
+	refund, err := client.Refund.Create(context.Background(), 450789469, Refund{
+		Notify: true,
+		Note:   "it broke during shipping",
+		RefundLineItems: []RefundLineItem{
+			{LineItemId: 703073504, Quantity: 1, RestockType: RefundRestockTypeReturn, LocationId: 487838322},
+		},
+		Transactions: []Transaction{
+			{ParentId: &parentId, Amount: &amount, Kind: "refund", Gateway: "bogus"},
+		},
+	})
+	if err != nil {
+		t.Errorf("Refund.Create returned error: %v", err)
+	}
+
+	refundTests(t, refund)
+}
@@ -0,0 +1,192 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls which failed requests are retried and how long the
+// client waits between attempts. See WithRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including
+	// the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// RetryStatusCodes are the response status codes that are retried.
+	RetryStatusCodes []int
+
+	// RetryNetworkErrors retries requests that failed without a response,
+	// e.g. on connection resets and timeouts.
+	RetryNetworkErrors bool
+
+	// BaseDelay is the delay before the first retry. It doubles with every
+	// further attempt up to MaxDelay. A 429 response waits for its
+	// Retry-After header instead.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff delay, zero means no cap.
+	MaxDelay time.Duration
+
+	// Jitter randomly shortens each backoff delay by up to this fraction
+	// (0 to 1) so that clients failing together do not retry together.
+	Jitter float64
+
+	// RetryNonIdempotent also retries POST and PATCH requests on every
+	// retryable status and network error. By default they are only retried
+	// when Shopify did not process them, i.e. on 429 and 503 responses.
+	RetryNonIdempotent bool
+
+	// OnRetry, if set, is called before the client waits for a retry, e.g.
+	// to record metrics.
+	OnRetry func(RetryInfo)
+}
+
+// RetryInfo describes a retry about to happen.
+type RetryInfo struct {
+	// Request that failed, nil for throttled GraphQL queries.
+	Request *http.Request
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt int
+	// StatusCode of the failed attempt, zero on network errors.
+	StatusCode int
+	// Err is the error of the failed attempt.
+	Err error
+	// Delay is the time the client waits before the next attempt.
+	Delay time.Duration
+}
+
+// DefaultRetryPolicy returns a policy retrying rate limits, server errors and
+// network errors up to 5 attempts with exponential backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		RetryStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+			520, // Cloudflare "unknown error", returned by Shopify's edge
+		},
+		RetryNetworkErrors: true,
+		BaseDelay:          500 * time.Millisecond,
+		MaxDelay:           30 * time.Second,
+		Jitter:             0.5,
+	}
+}
+
+// retryOnStatus reports whether a request failing with status should be
+// attempted again.
+func (p RetryPolicy) retryOnStatus(req *http.Request, status, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	retryable := false
+	for _, code := range p.RetryStatusCodes {
+		if code == status {
+			retryable = true
+			break
+		}
+	}
+	if !retryable {
+		return false
+	}
+
+	if isIdempotent(req.Method) || p.RetryNonIdempotent {
+		return true
+	}
+
+	// Shopify did not process the request
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// retryOnError reports whether a request failing with the network error err
+// should be attempted again.
+func (p RetryPolicy) retryOnError(req *http.Request, err error, attempt int) bool {
+	if attempt >= p.MaxAttempts || !p.RetryNetworkErrors {
+		return false
+	}
+
+	// the caller gave up, retrying is pointless
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if !isIdempotent(req.Method) && !p.RetryNonIdempotent {
+		return false
+	}
+
+	// *url.Error is a net.Error itself, look at what it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// backoff returns the delay after the given failed attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	d := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		d -= d * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// delay returns the delay after a failed attempt with response resp, which
+// is nil for network errors.
+func (p RetryPolicy) delay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if f, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && f > 0 {
+			return time.Duration(f * float64(time.Second))
+		}
+	}
+
+	return p.backoff(attempt)
+}
+
+// waitRetry notifies the OnRetry hook and waits for delay, or until the
+// request's context is done.
+func (p RetryPolicy) waitRetry(ctx context.Context, info RetryInfo) error {
+	if p.OnRetry != nil {
+		p.OnRetry(info)
+	}
+
+	if info.Delay <= 0 {
+		return ctx.Err()
+	}
+
+	return sleepContext(ctx, info.Delay)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func testRetryPolicy(retries *[]RetryInfo) RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	p.MaxDelay = 2 * time.Millisecond
+	p.OnRetry = func(info RetryInfo) {
+		*retries = append(*retries, info)
+	}
+	return p
+}
+
+func TestRetryPolicyStatusCodes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	cases := []struct {
+		method           string
+		status           int
+		nonIdempotent    bool
+		expectedAttempts int
+	}{
+		{"GET", http.StatusInternalServerError, false, 5},
+		{"GET", http.StatusBadGateway, false, 5},
+		{"GET", 520, false, 5},
+		{"GET", http.StatusNotFound, false, 1},
+		{"PUT", http.StatusGatewayTimeout, false, 5},
+		{"DELETE", http.StatusServiceUnavailable, false, 5},
+		{"POST", http.StatusInternalServerError, false, 1},
+		{"POST", http.StatusServiceUnavailable, false, 5},
+		{"POST", http.StatusInternalServerError, true, 5},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s %d", c.method, c.status), func(t *testing.T) {
+			httpmock.Reset()
+
+			var retries []RetryInfo
+			policy := testRetryPolicy(&retries)
+			policy.RetryNonIdempotent = c.nonIdempotent
+			WithRetryPolicy(policy)(client)
+
+			httpmock.RegisterResponder(c.method, "https://fooshop.myshopify.com/foo",
+				httpmock.NewStringResponder(c.status, `{"errors":"oops"}`))
+
+			req, err := client.NewRequest(context.Background(), c.method, "foo", nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = client.Do(req, nil)
+			expectedErr := ResponseError{Status: c.status, Message: "oops"}
+			if !reflect.DeepEqual(err, expectedErr) {
+				t.Errorf("Do(): expected error %#v, actual %#v", expectedErr, err)
+			}
+
+			if n := httpmock.GetTotalCallCount(); n != c.expectedAttempts {
+				t.Errorf("Do(): made %d attempts, expected %d", n, c.expectedAttempts)
+			}
+
+			if len(retries) != c.expectedAttempts-1 {
+				t.Fatalf("OnRetry called %d times, expected %d", len(retries), c.expectedAttempts-1)
+			}
+			for i, info := range retries {
+				if info.Attempt != i+1 || info.StatusCode != c.status || info.Request != req || info.Err == nil {
+					t.Errorf("OnRetry(%d) got %+v", i, info)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNetworkErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var retries []RetryInfo
+	WithRetryPolicy(testRetryPolicy(&retries))(client)
+
+	attempts := 0
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/foo",
+		func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, timeoutError{}
+			}
+			return httpmock.NewStringResponse(200, `{"foo":"bar"}`), nil
+		})
+
+	req, _ := client.NewRequest(context.Background(), "GET", "foo", nil, nil)
+	resource := struct {
+		Foo string `json:"foo"`
+	}{}
+	if err := client.Do(req, &resource); err != nil {
+		t.Fatalf("Do(): returned %v", err)
+	}
+
+	if resource.Foo != "bar" || attempts != 3 || len(retries) != 2 {
+		t.Errorf("Do(): got %+v after %d attempts and %d retries", resource, attempts, len(retries))
+	}
+	if retries[0].StatusCode != 0 || retries[0].Err == nil {
+		t.Errorf("OnRetry got %+v, expected a network error", retries[0])
+	}
+
+	// network errors of non idempotent requests are not retried
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/foo",
+		httpmock.NewErrorResponder(timeoutError{}))
+	req, _ = client.NewRequest(context.Background(), "POST", "foo", nil, nil)
+	if err := client.Do(req, nil); err == nil {
+		t.Error("Do(): expected an error")
+	}
+	if n := httpmock.GetCallCountInfo()["POST https://fooshop.myshopify.com/foo"]; n != 1 {
+		t.Errorf("Do(): made %d attempts, expected 1", n)
+	}
+
+	// other errors are not retried either
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/bar",
+		httpmock.NewErrorResponder(errors.New("boom")))
+	req, _ = client.NewRequest(context.Background(), "GET", "bar", nil, nil)
+	if err := client.Do(req, nil); err == nil {
+		t.Error("Do(): expected an error")
+	}
+	if n := httpmock.GetCallCountInfo()["GET https://fooshop.myshopify.com/bar"]; n != 1 {
+		t.Errorf("Do(): made %d attempts, expected 1", n)
+	}
+}
+
+func TestRetryPolicyContextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Hour
+	WithRetryPolicy(policy)(client)
+
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/foo",
+		httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, _ := client.NewRequest(ctx, "GET", "foo", nil, nil)
+
+	start := time.Now()
+	err := client.Do(req, nil)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Do(): returned %v, expected %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Do(): returned after %s", elapsed)
+	}
+}
+
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	p := DefaultRetryPolicy()
+
+	resp := httpmock.NewStringResponse(http.StatusTooManyRequests, "")
+	resp.Header.Set("Retry-After", "1.5")
+	if d := p.delay(resp, 1); d != 1500*time.Millisecond {
+		t.Errorf("RetryPolicy.delay() = %s, expected 1.5s", d)
+	}
+
+	resp.Header.Del("Retry-After")
+	if d := p.delay(resp, 1); d > p.BaseDelay {
+		t.Errorf("RetryPolicy.delay() = %s, expected at most %s", d, p.BaseDelay)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, e := range expected {
+		if d := p.backoff(i + 1); d != e {
+			t.Errorf("RetryPolicy.backoff(%d) = %s, expected %s", i+1, d, e)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := p.backoff(3); d < 2*time.Second || d > 4*time.Second {
+			t.Fatalf("RetryPolicy.backoff(3) = %s, expected between 2s and 4s", d)
+		}
+	}
+
+	if d := (RetryPolicy{}).backoff(3); d != 0 {
+		t.Errorf("RetryPolicy.backoff() without BaseDelay = %s, expected 0", d)
+	}
+}
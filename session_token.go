@@ -0,0 +1,197 @@
+package goshopify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sessionTokenLeeway is the clock skew tolerated when checking the exp and
+// nbf claims of a session token.
+const sessionTokenLeeway = 10 * time.Second
+
+// SessionTokenRetryHeader asks App Bridge to fetch a new session token and
+// retry the request, set on responses rejecting a session token.
+const SessionTokenRetryHeader = "X-Shopify-Retry-Invalid-Session-Request"
+
+// Errors of session token verification
+var (
+	ErrInvalidSessionToken = errors.New("invalid session token")
+	ErrSessionTokenExpired = errors.New("session token expired")
+)
+
+// SessionTokenClaims are the claims of a session token, the JWT App Bridge
+// sends with requests from an embedded app.
+// See: https://shopify.dev/docs/apps/build/authentication-authorization/session-tokens
+type SessionTokenClaims struct {
+	// Issuer is the shop's admin url, e.g.
+	// https://fooshop.myshopify.com/admin
+	Issuer string `json:"iss"`
+
+	// Dest is the shop's url, e.g. https://fooshop.myshopify.com
+	Dest string `json:"dest"`
+
+	// Audience is the ApiKey of the app
+	Audience string `json:"aud"`
+
+	// Subject is the id of the user
+	Subject string `json:"sub"`
+
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+	IssuedAt  int64  `json:"iat"`
+	Id        string `json:"jti"`
+	SessionId string `json:"sid"`
+	Signature string `json:"sig"`
+
+	// Shop is the myshopify.com domain of Dest, set once the token is
+	// verified
+	Shop string `json:"-"`
+
+	// UserId is the Subject as a number, set once the token is verified
+	UserId uint64 `json:"-"`
+
+	// Raw is the verified token itself, e.g. to exchange it for an access
+	// token with App.ExchangeSessionToken
+	Raw string `json:"-"`
+}
+
+// VerifySessionToken checks the signature of a session token with the
+// ApiSecret of the app, that it is valid at the current time, that it was
+// issued for the app and that its iss and dest claims are the same shop,
+// then returns its claims.
+//
+// Errors wrap ErrSessionTokenExpired for expired tokens and
+// ErrInvalidSessionToken otherwise.
+func (app App) VerifySessionToken(token string) (*SessionTokenClaims, error) {
+	return app.verifySessionToken(token, time.Now())
+}
+
+func (app App) verifySessionToken(token string, now time.Time) (*SessionTokenClaims, error) {
+	if app.ApiSecret == "" {
+		return nil, errors.New("ApiSecret is empty")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidSessionToken)
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidSessionToken)
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidSessionToken)
+	}
+	if h.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidSessionToken, h.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSessionToken)
+	}
+	mac := hmac.New(sha256.New, []byte(app.ApiSecret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidSessionToken)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidSessionToken)
+	}
+	claims := &SessionTokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidSessionToken)
+	}
+
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(sessionTokenLeeway)) {
+		return nil, ErrSessionTokenExpired
+	}
+	if now.Add(sessionTokenLeeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, fmt.Errorf("%w: not valid yet", ErrInvalidSessionToken)
+	}
+	if claims.Audience != app.ApiKey {
+		return nil, fmt.Errorf("%w: issued for %q", ErrInvalidSessionToken, claims.Audience)
+	}
+
+	dest, err := url.Parse(claims.Dest)
+	if err != nil || !ValidShopDomain(dest.Host) {
+		return nil, fmt.Errorf("%w: invalid dest %q", ErrInvalidSessionToken, claims.Dest)
+	}
+	iss, err := url.Parse(claims.Issuer)
+	if err != nil || iss.Host != dest.Host {
+		return nil, fmt.Errorf("%w: issuer %q does not match dest %q", ErrInvalidSessionToken, claims.Issuer, claims.Dest)
+	}
+	claims.Shop = dest.Host
+
+	if claims.Subject != "" {
+		if claims.UserId, err = strconv.ParseUint(claims.Subject, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid subject %q", ErrInvalidSessionToken, claims.Subject)
+		}
+	}
+
+	claims.Raw = token
+	return claims, nil
+}
+
+type sessionTokenContextKey struct{}
+
+// SessionTokenFromContext returns the claims of the session token verified
+// by SessionTokenMiddleware, the token itself in their Raw field.
+func SessionTokenFromContext(ctx context.Context) (*SessionTokenClaims, bool) {
+	claims, ok := ctx.Value(sessionTokenContextKey{}).(*SessionTokenClaims)
+	return claims, ok
+}
+
+// SessionTokenMiddleware verifies the session token of requests from an
+// embedded app, read from the bearer token of the Authorization header or
+// the id_token query parameter of the app's initial load, and passes its
+// claims to next in the request context, see SessionTokenFromContext.
+//
+// Requests without a valid session token get a 401 response with the
+// SessionTokenRetryHeader set, so that App Bridge retries them with a new
+// token.
+//
+//	http.Handle("/api/", app.SessionTokenMiddleware(apiHandler))
+func (app App) SessionTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" {
+			token = r.URL.Query().Get("id_token")
+		}
+
+		claims, err := app.VerifySessionToken(token)
+		if err != nil {
+			w.Header().Set(SessionTokenRetryHeader, "1")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), sessionTokenContextKey{}, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// bearerToken returns the bearer token of the Authorization header of r
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
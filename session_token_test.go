@@ -0,0 +1,161 @@
+package goshopify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// sessionToken returns a session token with claims signed with secret
+func sessionToken(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// sessionTokenClaims returns valid claims for fooshop and the test app at now
+func sessionTokenClaims(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":  "https://fooshop.myshopify.com/admin",
+		"dest": "https://fooshop.myshopify.com",
+		"aud":  "apikey",
+		"sub":  "42",
+		"exp":  now.Add(time.Minute).Unix(),
+		"nbf":  now.Unix(),
+		"iat":  now.Unix(),
+		"jti":  "f8912129-1af6-4cad-9ca3-76b0f7621087",
+		"sid":  "aaea182f2732d44c23057c0fea584021a4485b2bd25d3eb7fd349313ad24c685",
+	}
+}
+
+func TestAppVerifySessionToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	now := time.Now()
+	claims, err := app.VerifySessionToken(sessionToken(t, app.ApiSecret, sessionTokenClaims(now)))
+	if err != nil {
+		t.Fatalf("App.VerifySessionToken(): %v", err)
+	}
+
+	if claims.Shop != "fooshop.myshopify.com" {
+		t.Errorf("SessionTokenClaims.Shop = %q, expected fooshop.myshopify.com", claims.Shop)
+	}
+	if claims.UserId != 42 {
+		t.Errorf("SessionTokenClaims.UserId = %d, expected 42", claims.UserId)
+	}
+	if claims.SessionId != "aaea182f2732d44c23057c0fea584021a4485b2bd25d3eb7fd349313ad24c685" {
+		t.Errorf("SessionTokenClaims.SessionId = %q", claims.SessionId)
+	}
+}
+
+func TestAppVerifySessionTokenInvalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	now := time.Now()
+	valid := sessionToken(t, app.ApiSecret, sessionTokenClaims(now))
+
+	cases := []struct {
+		description string
+		token       string
+		expected    error
+	}{
+		{"malformed", "foo.bar", ErrInvalidSessionToken},
+		{"wrong secret", sessionToken(t, "other", sessionTokenClaims(now)), ErrInvalidSessionToken},
+		{"tampered signature", valid[:len(valid)-2] + "AA", ErrInvalidSessionToken},
+		{"expired", sessionToken(t, app.ApiSecret, sessionTokenClaims(now.Add(-2*time.Minute))), ErrSessionTokenExpired},
+		{"not valid yet", sessionToken(t, app.ApiSecret, sessionTokenClaims(now.Add(time.Minute))), ErrInvalidSessionToken},
+	}
+
+	claims := sessionTokenClaims(now)
+	claims["aud"] = "otherkey"
+	cases = append(cases, struct {
+		description string
+		token       string
+		expected    error
+	}{"other audience", sessionToken(t, app.ApiSecret, claims), ErrInvalidSessionToken})
+
+	claims = sessionTokenClaims(now)
+	claims["iss"] = "https://barshop.myshopify.com/admin"
+	cases = append(cases, struct {
+		description string
+		token       string
+		expected    error
+	}{"issuer mismatch", sessionToken(t, app.ApiSecret, claims), ErrInvalidSessionToken})
+
+	claims = sessionTokenClaims(now)
+	claims["dest"] = "https://example.com"
+	claims["iss"] = "https://example.com/admin"
+	cases = append(cases, struct {
+		description string
+		token       string
+		expected    error
+	}{"not a shop", sessionToken(t, app.ApiSecret, claims), ErrInvalidSessionToken})
+
+	for _, c := range cases {
+		if _, err := app.VerifySessionToken(c.token); !errors.Is(err, c.expected) {
+			t.Errorf("%s: App.VerifySessionToken() returned %v, expected %v", c.description, err, c.expected)
+		}
+	}
+
+	// within the tolerated clock skew
+	if _, err := app.verifySessionToken(valid, now.Add(time.Minute+5*time.Second)); err != nil {
+		t.Errorf("App.VerifySessionToken() returned %v within the clock skew", err)
+	}
+}
+
+func TestAppSessionTokenMiddleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var claims *SessionTokenClaims
+	h := app.SessionTokenMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ = SessionTokenFromContext(r.Context())
+	}))
+
+	token := sessionToken(t, app.ApiSecret, sessionTokenClaims(time.Now()))
+
+	req := httptest.NewRequest("GET", "https://app.example.com/api/products", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || claims == nil || claims.Shop != "fooshop.myshopify.com" {
+		t.Errorf("SessionTokenMiddleware responded %d with claims %+v", w.Code, claims)
+	}
+	if claims != nil && claims.Raw != token {
+		t.Errorf("SessionTokenClaims.Raw = %q, expected the bearer token", claims.Raw)
+	}
+
+	claims = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "https://app.example.com/?id_token="+token, nil))
+	if w.Code != http.StatusOK || claims == nil {
+		t.Errorf("SessionTokenMiddleware responded %d to an id_token", w.Code)
+	}
+
+	claims = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "https://app.example.com/api/products", nil))
+	if w.Code != http.StatusUnauthorized || claims != nil {
+		t.Errorf("SessionTokenMiddleware responded %d without a token, expected %d", w.Code, http.StatusUnauthorized)
+	}
+	if w.Header().Get(SessionTokenRetryHeader) != "1" {
+		t.Errorf("SessionTokenMiddleware did not set %s", SessionTokenRetryHeader)
+	}
+}
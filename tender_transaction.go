@@ -0,0 +1,112 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const tenderTransactionsBasePath = "tender_transactions"
+
+// TenderTransactionService is an interface for interfacing with the tender
+// transaction endpoints of the Shopify API.
+// See: https://shopify.dev/docs/api/admin-rest/latest/resources/tendertransaction
+type TenderTransactionService interface {
+	List(context.Context, interface{}) ([]TenderTransaction, error)
+	ListAll(context.Context, interface{}) ([]TenderTransaction, error)
+	ListWithPagination(context.Context, interface{}) ([]TenderTransaction, *Pagination, error)
+}
+
+// TenderTransactionServiceOp handles communication with the tender
+// transaction related methods of the Shopify API.
+type TenderTransactionServiceOp struct {
+	client *Client
+}
+
+// TenderTransaction is money received or refunded for an order, one per
+// payment method used.
+type TenderTransaction struct {
+	Id              uint64                           `json:"id,omitempty"`
+	OrderId         uint64                           `json:"order_id,omitempty"`
+	Amount          decimal.Decimal                  `json:"amount"`
+	Currency        string                           `json:"currency,omitempty"`
+	UserId          uint64                           `json:"user_id,omitempty"`
+	Test            bool                             `json:"test,omitempty"`
+	ProcessedAt     *time.Time                       `json:"processed_at,omitempty"`
+	RemoteReference string                           `json:"remote_reference,omitempty"`
+	PaymentMethod   string                           `json:"payment_method,omitempty"`
+	PaymentDetails  *TenderTransactionPaymentDetails `json:"payment_details,omitempty"`
+}
+
+// TenderTransactionPaymentDetails are the card details of a tender
+// transaction, nil for other payment methods
+type TenderTransactionPaymentDetails struct {
+	CreditCardNumber  string `json:"credit_card_number,omitempty"`
+	CreditCardCompany string `json:"credit_card_company,omitempty"`
+}
+
+// A struct for all available tender transaction list options
+type TenderTransactionListOptions struct {
+	PageInfo       string    `url:"page_info,omitempty"`
+	Limit          int       `url:"limit,omitempty"`
+	SinceId        uint64    `url:"since_id,omitempty"`
+	ProcessedAt    time.Time `url:"processed_at,omitempty"`
+	ProcessedAtMin time.Time `url:"processed_at_min,omitempty"`
+	ProcessedAtMax time.Time `url:"processed_at_max,omitempty"`
+
+	// Order sorts the results, "processed_at ASC" or "processed_at DESC"
+	Order string `url:"order,omitempty"`
+}
+
+// Represents the result from the tender_transactions.json endpoint
+type TenderTransactionsResource struct {
+	TenderTransactions []TenderTransaction `json:"tender_transactions"`
+}
+
+// List tender transactions
+func (s *TenderTransactionServiceOp) List(ctx context.Context, options interface{}) ([]TenderTransaction, error) {
+	transactions, _, err := s.ListWithPagination(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// ListAll Lists all tender transactions, iterating over pages
+func (s *TenderTransactionServiceOp) ListAll(ctx context.Context, options interface{}) ([]TenderTransaction, error) {
+	collector := []TenderTransaction{}
+
+	for {
+		entities, pagination, err := s.ListWithPagination(ctx, options)
+
+		if err != nil {
+			return collector, err
+		}
+
+		collector = append(collector, entities...)
+
+		if pagination.NextPageOptions == nil {
+			break
+		}
+
+		options = pagination.NextPageOptions
+	}
+
+	return collector, nil
+}
+
+// ListWithPagination lists tender transactions and returns pagination to
+// retrieve next/previous results.
+func (s *TenderTransactionServiceOp) ListWithPagination(ctx context.Context, options interface{}) ([]TenderTransaction, *Pagination, error) {
+	path := fmt.Sprintf("%s.json", tenderTransactionsBasePath)
+	resource := new(TenderTransactionsResource)
+
+	pagination, err := s.client.ListWithPagination(ctx, path, resource, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource.TenderTransactions, pagination, nil
+}
@@ -0,0 +1,130 @@
+package goshopify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/shopspring/decimal"
+)
+
+func TestTenderTransactionList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/tender_transactions.json", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"tender_transactions": []}`))
+
+	params := map[string]string{
+		"processed_at_min": "2024-01-01T00:00:00Z",
+		"processed_at_max": "2024-02-01T00:00:00Z",
+		"order":            "processed_at ASC",
+	}
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/tender_transactions.json", client.pathPrefix),
+		params, httpmock.NewBytesResponder(200, loadFixture("tender_transactions.json")))
+
+	transactions, err := client.TenderTransaction.List(context.Background(), TenderTransactionListOptions{
+		ProcessedAtMin: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		ProcessedAtMax: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		Order:          "processed_at ASC",
+	})
+	if err != nil {
+		t.Errorf("TenderTransaction.List returned error: %v", err)
+	}
+
+	processedAt := time.Date(2024, time.January, 2, 14, 5, 12, 0, time.UTC)
+	expected := TenderTransaction{
+		Id:              1011222896,
+		OrderId:         450789469,
+		Amount:          decimal.NewFromFloat(250.94),
+		Currency:        "USD",
+		ProcessedAt:     &processedAt,
+		RemoteReference: "authorization-key",
+		PaymentMethod:   "credit_card",
+		PaymentDetails: &TenderTransactionPaymentDetails{
+			CreditCardNumber:  "•••• •••• •••• 4242",
+			CreditCardCompany: "Visa",
+		},
+	}
+
+	if len(transactions) != 2 {
+		t.Fatalf("TenderTransaction.List got %v transactions, expected: 2", len(transactions))
+	}
+
+	actual := transactions[0]
+	if !actual.Amount.Equal(expected.Amount) || !actual.ProcessedAt.Equal(processedAt) {
+		t.Errorf("TenderTransaction.List returned amount %s processed at %v, expected %s at %v", actual.Amount, actual.ProcessedAt, expected.Amount, processedAt)
+	}
+	actual.Amount, actual.ProcessedAt = expected.Amount, expected.ProcessedAt
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("TenderTransaction.List returned %+v, expected %+v", actual, expected)
+	}
+
+	if transactions[1].PaymentDetails != nil || !transactions[1].Amount.Equal(decimal.NewFromInt(-10)) {
+		t.Errorf("TenderTransaction.List returned %+v", transactions[1])
+	}
+}
+
+func TestTenderTransactionListAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	listURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/tender_transactions.json", client.pathPrefix)
+
+	httpmock.RegisterResponder("GET", listURL,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewBytesResponse(200, loadFixture("tender_transactions.json"))
+			resp.Header.Set("Link", fmt.Sprintf(`<%s?page_info=pg2&limit=2>; rel="next"`, listURL))
+			return resp, nil
+		})
+	httpmock.RegisterResponderWithQuery("GET", listURL, map[string]string{"page_info": "pg2", "limit": "2"},
+		httpmock.NewStringResponder(200, `{"tender_transactions": [{"id": 1, "amount": "1.00"}]}`))
+
+	transactions, err := client.TenderTransaction.ListAll(context.Background(), TenderTransactionListOptions{Limit: 2})
+	if err != nil {
+		t.Errorf("TenderTransaction.ListAll returned error: %v", err)
+	}
+
+	if len(transactions) != 3 {
+		t.Errorf("TenderTransaction.ListAll got %v transactions, expected: 3", len(transactions))
+	}
+}
+
+func TestTenderTransactionListWithPagination(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/tender_transactions.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewBytesResponse(200, loadFixture("tender_transactions.json"))
+			resp.Header.Set("Link", `<http://valid.url?page_info=pageInfoCode&limit=2>; rel="next"`)
+			return resp, nil
+		})
+
+	_, pagination, err := client.TenderTransaction.ListWithPagination(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("TenderTransaction.ListWithPagination returned error: %v", err)
+	}
+
+	expectedPageInfo := &ListOptions{PageInfo: "pageInfoCode", Limit: 2}
+	if !reflect.DeepEqual(pagination.NextPageOptions, expectedPageInfo) {
+		t.Errorf("TenderTransaction.ListWithPagination next page returned %+v, expected %+v", pagination.NextPageOptions, expectedPageInfo)
+	}
+}
+
+func TestTenderTransactionListError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/tender_transactions.json", client.pathPrefix),
+		httpmock.NewStringResponder(500, ""))
+
+	transactions, err := client.TenderTransaction.List(context.Background(), nil)
+	if err == nil || transactions != nil {
+		t.Errorf("TenderTransaction.List returned %+v, %v, expected an error", transactions, err)
+	}
+}
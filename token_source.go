@@ -0,0 +1,145 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultTokenRefreshBefore is how long before its expiry a cached token is
+// refreshed.
+const defaultTokenRefreshBefore = time.Minute
+
+// AccessToken is an access token with its expiry, zero if it does not
+// expire.
+type AccessToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TokenSource supplies the access token of a Client, consulted by NewRequest
+// on every request, see WithTokenSource. Token returns either a token or an
+// error. Implementations must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (*AccessToken, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource
+type TokenSourceFunc func(ctx context.Context) (*AccessToken, error)
+
+// Token calls f
+func (f TokenSourceFunc) Token(ctx context.Context) (*AccessToken, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource always returning token
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (*AccessToken, error) {
+		return &AccessToken{Token: token}, nil
+	})
+}
+
+// tokenInvalidator is implemented by token sources that can drop a token
+// rejected by Shopify, so that the request is retried once with a new one.
+type tokenInvalidator interface {
+	Invalidate(token string)
+}
+
+// CachingTokenSource caches the token of another TokenSource until it is
+// about to expire or is rejected with a 401 response. Concurrent callers
+// share a single refresh.
+type CachingTokenSource struct {
+	src TokenSource
+
+	// RefreshBefore is how long before its expiry a token is refreshed,
+	// defaults to 1 minute.
+	RefreshBefore time.Duration
+
+	mu    sync.Mutex
+	token *AccessToken
+
+	// test hook
+	now func() time.Time
+}
+
+// NewCachingTokenSource returns a CachingTokenSource over src, e.g. a
+// TokenSourceFunc reading the token of the shop from a database or
+// requesting a new one.
+func NewCachingTokenSource(src TokenSource) *CachingTokenSource {
+	return &CachingTokenSource{src: src, now: time.Now}
+}
+
+// Token returns the cached token, refreshing it from the underlying source
+// when there is none or it is about to expire.
+func (s *CachingTokenSource) Token(ctx context.Context) (*AccessToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && !s.expiring(s.token) {
+		return s.token, nil
+	}
+
+	token, err := s.src.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.Token == "" {
+		return nil, errors.New("token source returned no token")
+	}
+	s.token = token
+	return token, nil
+}
+
+func (s *CachingTokenSource) expiring(token *AccessToken) bool {
+	if token.ExpiresAt.IsZero() {
+		return false
+	}
+	refreshBefore := s.RefreshBefore
+	if refreshBefore <= 0 {
+		refreshBefore = defaultTokenRefreshBefore
+	}
+	return !s.now().Add(refreshBefore).Before(token.ExpiresAt)
+}
+
+// Invalidate drops the cached token if it is token, so that the next call
+// to Token refreshes it.
+func (s *CachingTokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && s.token.Token == token {
+		s.token = nil
+	}
+}
+
+// ClientCredentialsTokenSource returns a CachingTokenSource requesting
+// access tokens for shopName with the client credentials grant, available
+// to apps installed on stores of their own organization. The Client of app,
+// if set, must not use the returned source itself.
+func (app App) ClientCredentialsTokenSource(shopName string) *CachingTokenSource {
+	return NewCachingTokenSource(TokenSourceFunc(func(ctx context.Context) (*AccessToken, error) {
+		issuedAt := time.Now()
+		resp, err := app.GetClientCredentialsToken(ctx, shopName)
+		if err != nil {
+			return nil, err
+		}
+		return &AccessToken{Token: resp.AccessToken, ExpiresAt: resp.ExpiresAt(issuedAt)}, nil
+	}))
+}
+
+// GetClientCredentialsToken requests an access token for shopName with the
+// client credentials grant.
+func (app App) GetClientCredentialsToken(ctx context.Context, shopName string) (*AccessTokenResponse, error) {
+	data := struct {
+		ClientId     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		GrantType    string `json:"grant_type"`
+	}{
+		ClientId:     app.ApiKey,
+		ClientSecret: app.ApiSecret,
+		GrantType:    "client_credentials",
+	}
+
+	return app.requestAccessToken(ctx, shopName, data)
+}
@@ -0,0 +1,157 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestCachingTokenSource(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	src := NewCachingTokenSource(TokenSourceFunc(func(ctx context.Context) (*AccessToken, error) {
+		calls++
+		return &AccessToken{Token: fmt.Sprintf("token%d", calls), ExpiresAt: now.Add(time.Hour)}, nil
+	}))
+	src.now = func() time.Time { return now }
+
+	token, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("CachingTokenSource.Token(): %v", err)
+	}
+	if token.Token != "token1" {
+		t.Errorf("CachingTokenSource.Token() returned %s, expected token1", token.Token)
+	}
+
+	// cached until about to expire
+	now = now.Add(58 * time.Minute)
+	if token, _ = src.Token(context.Background()); token.Token != "token1" {
+		t.Errorf("CachingTokenSource.Token() returned %s, expected the cached token1", token.Token)
+	}
+
+	now = now.Add(time.Minute)
+	if token, _ = src.Token(context.Background()); token.Token != "token2" {
+		t.Errorf("CachingTokenSource.Token() returned %s, expected the refreshed token2", token.Token)
+	}
+
+	// only the current token is invalidated
+	src.Invalidate("token1")
+	if token, _ = src.Token(context.Background()); token.Token != "token2" {
+		t.Errorf("CachingTokenSource.Token() returned %s after invalidating an old token, expected token2", token.Token)
+	}
+	src.Invalidate("token2")
+	if token, _ = src.Token(context.Background()); token.Token != "token3" {
+		t.Errorf("CachingTokenSource.Token() returned %s after invalidating it, expected token3", token.Token)
+	}
+}
+
+func TestCachingTokenSourceError(t *testing.T) {
+	expected := errors.New("no token")
+	src := NewCachingTokenSource(TokenSourceFunc(func(ctx context.Context) (*AccessToken, error) {
+		return nil, expected
+	}))
+
+	if _, err := src.Token(context.Background()); err != expected {
+		t.Errorf("CachingTokenSource.Token() returned %v, expected %v", err, expected)
+	}
+}
+
+func TestWithTokenSource(t *testing.T) {
+	setup()
+	defer teardown()
+
+	token := "first"
+	c := MustNewClient(app, "fooshop", "ignored", WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*AccessToken, error) {
+		return &AccessToken{Token: token}, nil
+	})))
+
+	for _, expected := range []string{"first", "rotated"} {
+		token = expected
+		req, err := c.NewRequest(context.Background(), "GET", "foo", nil, nil)
+		if err != nil {
+			t.Fatalf("NewRequest(): %v", err)
+		}
+		if actual := req.Header.Get("X-Shopify-Access-Token"); actual != expected {
+			t.Errorf("NewRequest() X-Shopify-Access-Token = %s, expected %s", actual, expected)
+		}
+	}
+
+	failing := MustNewClient(app, "fooshop", "", WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*AccessToken, error) {
+		return nil, errors.New("no token")
+	})))
+	if _, err := failing.NewRequest(context.Background(), "GET", "foo", nil, nil); err == nil {
+		t.Error("NewRequest() expected the error of the token source")
+	}
+}
+
+func TestTokenSourceRetryOnUnauthorized(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	src := NewCachingTokenSource(TokenSourceFunc(func(ctx context.Context) (*AccessToken, error) {
+		calls++
+		return &AccessToken{Token: fmt.Sprintf("token%d", calls)}, nil
+	}))
+	c := MustNewClient(app, "fooshop", "", WithTokenSource(src))
+	httpmock.ActivateNonDefault(c.Client)
+
+	var tokens []string
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/foo",
+		func(req *http.Request) (*http.Response, error) {
+			tokens = append(tokens, req.Header.Get("X-Shopify-Access-Token"))
+			if req.Header.Get("X-Shopify-Access-Token") == "token1" {
+				return httpmock.NewStringResponse(401, `{"errors":"[API] Invalid API key or access token (unrecognized login or wrong password)"}`), nil
+			}
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+	req, err := c.NewRequest(context.Background(), "GET", "foo", nil, nil)
+	if err != nil {
+		t.Fatalf("NewRequest(): %v", err)
+	}
+	if err := c.Do(req, nil); err != nil {
+		t.Fatalf("Client.Do(): %v", err)
+	}
+	if len(tokens) != 2 || tokens[0] != "token1" || tokens[1] != "token2" {
+		t.Errorf("Client.Do() sent tokens %v, expected [token1 token2]", tokens)
+	}
+
+	// retried only once
+	src.Invalidate("token2")
+	httpmock.RegisterResponder("GET", "https://fooshop.myshopify.com/bar",
+		httpmock.NewStringResponder(401, `{"errors":"[API] Invalid API key or access token (unrecognized login or wrong password)"}`))
+	req, _ = c.NewRequest(context.Background(), "GET", "bar", nil, nil)
+	err = c.Do(req, nil)
+	if _, ok := err.(UnauthorizedError); !ok {
+		t.Errorf("Client.Do() returned %v, expected an UnauthorizedError", err)
+	}
+	if calls != 4 {
+		t.Errorf("token source called %d times, expected 4", calls)
+	}
+}
+
+func TestAppClientCredentialsTokenSource(t *testing.T) {
+	setup()
+	defer teardown()
+
+	httpmock.RegisterResponder("POST", "https://fooshop.myshopify.com/admin/oauth/access_token",
+		httpmock.NewStringResponder(200, `{"access_token":"footoken","scope":"read_products","expires_in":86399}`))
+
+	tokenApp := app
+	tokenApp.Client = client
+
+	before := time.Now()
+	token, err := tokenApp.ClientCredentialsTokenSource("fooshop").Token(context.Background())
+	if err != nil {
+		t.Fatalf("ClientCredentialsTokenSource.Token(): %v", err)
+	}
+	if token.Token != "footoken" || token.ExpiresAt.Before(before.Add(86399*time.Second)) {
+		t.Errorf("ClientCredentialsTokenSource.Token() returned %+v", token)
+	}
+}
@@ -3,10 +3,13 @@ package goshopify
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 )
 
+var shopDomainRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9\-]*\.myshopify\.com$`)
+
 // Return the full shop name, including .myshopify.com
 func ShopFullName(name string) string {
 	name = strings.TrimSpace(name)
@@ -25,6 +28,13 @@ func ShopShortName(name string) string {
 	return strings.Replace(ShopFullName(name), ".myshopify.com", "", -1)
 }
 
+// ValidShopDomain reports whether shop is a valid myshopify.com hostname,
+// e.g. "fooshop.myshopify.com". Check the shop parameter of requests with it
+// before calling the shop's API.
+func ValidShopDomain(shop string) bool {
+	return shopDomainRegex.MatchString(shop)
+}
+
 // Return the Shop's base url.
 func ShopBaseUrl(name string) string {
 	name = ShopFullName(name)
@@ -49,6 +59,15 @@ func FulfillmentPathPrefix(resource string, resourceId uint64) string {
 	return prefix
 }
 
+// Return the prefix for an event path
+func EventPathPrefix(resource string, resourceId uint64) string {
+	prefix := "events"
+	if resource != "" {
+		prefix = fmt.Sprintf("%s/%d/events", resource, resourceId)
+	}
+	return prefix
+}
+
 type OnlyDate struct {
 	time.Time
 }
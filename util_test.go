@@ -67,6 +67,29 @@ func TestShopBaseUrl(t *testing.T) {
 	}
 }
 
+func TestValidShopDomain(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected bool
+	}{
+		{"myshop.myshopify.com", true},
+		{"my-shop-2.myshopify.com", true},
+		{"myshop", false},
+		{"-myshop.myshopify.com", false},
+		{"myshop.myshopify.com.evil.com", false},
+		{"evil.com/myshop.myshopify.com", false},
+		{"my.shop.myshopify.com", false},
+		{"myshop.myshopifyXcom", false},
+	}
+
+	for _, c := range cases {
+		actual := ValidShopDomain(c.in)
+		if actual != c.expected {
+			t.Errorf("ValidShopDomain(%s): expected %v, actual %v", c.in, c.expected, actual)
+		}
+	}
+}
+
 func TestMetafieldPathPrefix(t *testing.T) {
 	cases := []struct {
 		resource   string
@@ -103,6 +126,24 @@ func TestFulfillmentPathPrefix(t *testing.T) {
 	}
 }
 
+func TestEventPathPrefix(t *testing.T) {
+	cases := []struct {
+		resource   string
+		resourceId uint64
+		expected   string
+	}{
+		{"", 0, "events"},
+		{"products", 123, "products/123/events"},
+	}
+
+	for _, c := range cases {
+		actual := EventPathPrefix(c.resource, c.resourceId)
+		if actual != c.expected {
+			t.Errorf("EventPathPrefix(%s, %d): expected %s, actual %s", c.resource, c.resourceId, c.expected, actual)
+		}
+	}
+}
+
 func TestOnlyDateMarshal(t *testing.T) {
 	cases := []struct {
 		in       OnlyDate
@@ -3,6 +3,7 @@ package goshopify
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -18,6 +19,7 @@ type WebhookService interface {
 	Create(context.Context, Webhook) (*Webhook, error)
 	Update(context.Context, Webhook) (*Webhook, error)
 	Delete(context.Context, uint64) error
+	Sync(context.Context, []Webhook, *WebhookSyncOptions) (*WebhookSyncReport, error)
 }
 
 // WebhookServiceOp handles communication with the webhook-related methods of
@@ -46,6 +48,34 @@ type WebhookOptions struct {
 	Topic   string `url:"topic,omitempty"`
 }
 
+// WebhookSyncOptions change how Sync reconciles webhooks.
+type WebhookSyncOptions struct {
+	// DryRun reports the changes Sync would make without making them.
+	DryRun bool
+
+	// DeleteExtra deletes the existing webhooks that are not desired.
+	DeleteExtra bool
+}
+
+// WebhookSyncReport lists the changes made by Sync, or those it would make
+// in a dry run.
+type WebhookSyncReport struct {
+	// Created holds the created webhooks, the desired ones in a dry run.
+	Created []Webhook
+	// Updated holds the updated webhooks, the desired ones with the id of
+	// the webhook to update in a dry run.
+	Updated []Webhook
+	// Deleted holds the existing webhooks that were deleted.
+	Deleted []Webhook
+	// Unchanged holds the existing webhooks already matching the desired
+	// ones.
+	Unchanged []Webhook
+	// Extra holds the existing webhooks that are not desired and were kept
+	// because DeleteExtra was not set.
+	Extra  []Webhook
+	DryRun bool
+}
+
 // WebhookResource represents the result from the admin/webhooks.json endpoint
 type WebhookResource struct {
 	Webhook *Webhook `json:"webhook"`
@@ -78,8 +108,13 @@ func (s *WebhookServiceOp) Get(ctx context.Context, webhookdId uint64, options i
 	return resource.Webhook, err
 }
 
-// Create a new webhook
+// Create a new webhook. Topics missing from the registry of webhook topics
+// fail with ErrUnknownWebhookTopic, and compliance topics with
+// ErrComplianceWebhookTopic, without calling the API.
 func (s *WebhookServiceOp) Create(ctx context.Context, webhook Webhook) (*Webhook, error) {
+	if err := validateWebhookTopic(webhook.Topic); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("%s.json", webhooksBasePath)
 	wrappedData := WebhookResource{Webhook: &webhook}
 	resource := new(WebhookResource)
@@ -87,8 +122,13 @@ func (s *WebhookServiceOp) Create(ctx context.Context, webhook Webhook) (*Webhoo
 	return resource.Webhook, err
 }
 
-// Update an existing webhook.
+// Update an existing webhook. A topic, if set, is validated like in Create.
 func (s *WebhookServiceOp) Update(ctx context.Context, webhook Webhook) (*Webhook, error) {
+	if webhook.Topic != "" {
+		if err := validateWebhookTopic(webhook.Topic); err != nil {
+			return nil, err
+		}
+	}
 	path := fmt.Sprintf("%s/%d.json", webhooksBasePath, webhook.Id)
 	wrappedData := WebhookResource{Webhook: &webhook}
 	resource := new(WebhookResource)
@@ -100,3 +140,126 @@ func (s *WebhookServiceOp) Update(ctx context.Context, webhook Webhook) (*Webhoo
 func (s *WebhookServiceOp) Delete(ctx context.Context, Id uint64) error {
 	return s.client.Delete(ctx, fmt.Sprintf("%s/%d.json", webhooksBasePath, Id))
 }
+
+// Sync reconciles the webhooks of the shop with the desired ones. Desired
+// webhooks are matched with existing ones by topic and address, then by
+// topic alone. Missing webhooks are created, existing ones whose address,
+// format, fields or metafield namespaces differ are updated, an empty format
+// keeping the existing one, and, with DeleteExtra, webhooks that are not
+// desired are deleted. Sync stops at the first failed request and returns
+// the changes made so far along with the error.
+func (s *WebhookServiceOp) Sync(ctx context.Context, desired []Webhook, options *WebhookSyncOptions) (*WebhookSyncReport, error) {
+	if options == nil {
+		options = &WebhookSyncOptions{}
+	}
+	report := &WebhookSyncReport{DryRun: options.DryRun}
+
+	var existing []Webhook
+	it := s.client.NewListIterator(fmt.Sprintf("%s.json", webhooksBasePath), new(WebhooksResource), ListOptions{Limit: 250})
+	err := it.Each(ctx, func(item interface{}) error {
+		existing = append(existing, item.(Webhook))
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	matched := make([]*Webhook, len(desired))
+	used := make([]bool, len(existing))
+	match := func(sameAddress bool) {
+		for i, d := range desired {
+			if matched[i] != nil {
+				continue
+			}
+			for j, e := range existing {
+				if used[j] || e.Topic != d.Topic || (sameAddress && e.Address != d.Address) {
+					continue
+				}
+				matched[i] = &existing[j]
+				used[j] = true
+				break
+			}
+		}
+	}
+	match(true)
+	match(false)
+
+	for i, d := range desired {
+		e := matched[i]
+		switch {
+		case e == nil:
+			if options.DryRun {
+				report.Created = append(report.Created, d)
+				continue
+			}
+			created, err := s.Create(ctx, d)
+			if err != nil {
+				return report, err
+			}
+			report.Created = append(report.Created, *created)
+
+		case webhookChanged(*e, d):
+			d.Id = e.Id
+			if d.Format == "" {
+				d.Format = e.Format
+			}
+			if options.DryRun {
+				report.Updated = append(report.Updated, d)
+				continue
+			}
+			updated, err := s.Update(ctx, d)
+			if err != nil {
+				return report, err
+			}
+			report.Updated = append(report.Updated, *updated)
+
+		default:
+			report.Unchanged = append(report.Unchanged, *e)
+		}
+	}
+
+	for j, e := range existing {
+		if used[j] {
+			continue
+		}
+		if !options.DeleteExtra {
+			report.Extra = append(report.Extra, e)
+			continue
+		}
+		if !options.DryRun {
+			if err := s.Delete(ctx, e.Id); err != nil {
+				return report, err
+			}
+		}
+		report.Deleted = append(report.Deleted, e)
+	}
+
+	return report, nil
+}
+
+// webhookChanged reports whether the existing webhook differs from the
+// desired one in a field Sync updates. An empty desired format keeps the
+// existing one.
+func webhookChanged(existing, desired Webhook) bool {
+	return existing.Address != desired.Address ||
+		(desired.Format != "" && existing.Format != desired.Format) ||
+		!sameStringSet(existing.Fields, desired.Fields) ||
+		!sameStringSet(existing.MetafieldNamespaces, desired.MetafieldNamespaces)
+}
+
+// sameStringSet reports whether a and b hold the same strings, in any order
+func sameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+package goshopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Mandatory compliance webhook topics, configured in the app's settings
+// rather than through the webhook API.
+// See: https://shopify.dev/docs/apps/build/privacy-law-compliance
+const (
+	WebhookTopicCustomersDataRequest = "customers/data_request"
+	WebhookTopicCustomersRedact      = "customers/redact"
+	WebhookTopicShopRedact           = "shop/redact"
+)
+
+// ErrComplianceWebhookTopic is returned when creating or updating a webhook
+// with a compliance topic, which Shopify does not allow through the API.
+var ErrComplianceWebhookTopic = errors.New("compliance webhook topic must be configured in the app settings")
+
+func isComplianceWebhookTopic(topic string) bool {
+	switch topic {
+	case WebhookTopicCustomersDataRequest, WebhookTopicCustomersRedact, WebhookTopicShopRedact:
+		return true
+	}
+	return false
+}
+
+// ComplianceCustomer is the customer of a compliance webhook
+type ComplianceCustomer struct {
+	Id    uint64 `json:"id"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// CustomersDataRequestPayload is the payload of the customers/data_request
+// webhook, sent when a customer requests their data from a store owner.
+type CustomersDataRequestPayload struct {
+	ShopId          uint64             `json:"shop_id"`
+	ShopDomain      string             `json:"shop_domain"`
+	OrdersRequested []uint64           `json:"orders_requested"`
+	Customer        ComplianceCustomer `json:"customer"`
+	DataRequest     struct {
+		Id uint64 `json:"id"`
+	} `json:"data_request"`
+}
+
+// CustomersRedactPayload is the payload of the customers/redact webhook,
+// sent when a store owner requests the deletion of a customer's data.
+type CustomersRedactPayload struct {
+	ShopId         uint64             `json:"shop_id"`
+	ShopDomain     string             `json:"shop_domain"`
+	Customer       ComplianceCustomer `json:"customer"`
+	OrdersToRedact []uint64           `json:"orders_to_redact"`
+}
+
+// ShopRedactPayload is the payload of the shop/redact webhook, sent 48 hours
+// after a store owner uninstalled the app.
+type ShopRedactPayload struct {
+	ShopId     uint64 `json:"shop_id"`
+	ShopDomain string `json:"shop_domain"`
+}
+
+// ComplianceAuditRecord records the handling of a compliance webhook
+type ComplianceAuditRecord struct {
+	Topic      string
+	ShopDomain string
+	ShopId     uint64
+	WebhookId  string
+
+	// CustomerId is zero for shop/redact
+	CustomerId uint64
+
+	TriggeredAt time.Time
+	ReceivedAt  time.Time
+	FulfilledAt time.Time
+
+	// Err is the error returned by the handler, nil if the request was
+	// fulfilled.
+	Err error
+}
+
+// ComplianceHandlers are the app's handlers of the compliance webhooks.
+// Topics without a handler are acknowledged without being audited.
+type ComplianceHandlers struct {
+	CustomersDataRequest func(ctx context.Context, payload *CustomersDataRequestPayload) error
+	CustomersRedact      func(ctx context.Context, payload *CustomersRedactPayload) error
+	ShopRedact           func(ctx context.Context, payload *ShopRedactPayload) error
+
+	// Audit, if set, is called once a handler returned, e.g. to keep
+	// evidence of when each request was fulfilled.
+	Audit func(ctx context.Context, record ComplianceAuditRecord)
+}
+
+// NewComplianceWebhookHandler returns a WebhookHandler verifying compliance
+// webhooks with the ApiSecret of app and dispatching them to handlers. More
+// topics can be registered on it with HandleFunc. Payloads are decoded into
+// the compliance payload types whatever the types registered for their
+// topics with RegisterWebhookTopic, payloads that cannot be decoded get the
+// DecodeErrorStatus of the handler.
+func NewComplianceWebhookHandler(app App, handlers ComplianceHandlers) *WebhookHandler {
+	h := NewWebhookHandler(app)
+
+	if handlers.CustomersDataRequest != nil {
+		h.HandleFunc(WebhookTopicCustomersDataRequest, func(ctx context.Context, event *WebhookEvent) error {
+			record := newComplianceAuditRecord(event)
+			payload := &CustomersDataRequestPayload{}
+			if err := decodeCompliancePayload(event, payload); err != nil {
+				handlers.audit(ctx, record, err)
+				return err
+			}
+			record.ShopId = payload.ShopId
+			record.CustomerId = payload.Customer.Id
+			err := handlers.CustomersDataRequest(ctx, payload)
+			handlers.audit(ctx, record, err)
+			return err
+		})
+	}
+
+	if handlers.CustomersRedact != nil {
+		h.HandleFunc(WebhookTopicCustomersRedact, func(ctx context.Context, event *WebhookEvent) error {
+			record := newComplianceAuditRecord(event)
+			payload := &CustomersRedactPayload{}
+			if err := decodeCompliancePayload(event, payload); err != nil {
+				handlers.audit(ctx, record, err)
+				return err
+			}
+			record.ShopId = payload.ShopId
+			record.CustomerId = payload.Customer.Id
+			err := handlers.CustomersRedact(ctx, payload)
+			handlers.audit(ctx, record, err)
+			return err
+		})
+	}
+
+	if handlers.ShopRedact != nil {
+		h.HandleFunc(WebhookTopicShopRedact, func(ctx context.Context, event *WebhookEvent) error {
+			record := newComplianceAuditRecord(event)
+			payload := &ShopRedactPayload{}
+			if err := decodeCompliancePayload(event, payload); err != nil {
+				handlers.audit(ctx, record, err)
+				return err
+			}
+			record.ShopId = payload.ShopId
+			err := handlers.ShopRedact(ctx, payload)
+			handlers.audit(ctx, record, err)
+			return err
+		})
+	}
+
+	return h
+}
+
+// decodeCompliancePayload decodes the body of event into payload
+func decodeCompliancePayload(event *WebhookEvent, payload interface{}) error {
+	if err := json.Unmarshal(event.Body, payload); err != nil {
+		return webhookDecodeError{fmt.Errorf("webhook %s payload: %w", event.Topic, err)}
+	}
+	return nil
+}
+
+func newComplianceAuditRecord(event *WebhookEvent) ComplianceAuditRecord {
+	return ComplianceAuditRecord{
+		Topic:       event.Topic,
+		ShopDomain:  event.ShopDomain,
+		WebhookId:   event.WebhookId,
+		TriggeredAt: event.TriggeredAt,
+		ReceivedAt:  time.Now(),
+	}
+}
+
+func (h ComplianceHandlers) audit(ctx context.Context, record ComplianceAuditRecord, err error) {
+	if h.Audit == nil {
+		return
+	}
+	record.Err = err
+	if err == nil {
+		record.FulfilledAt = time.Now()
+	}
+	h.Audit(ctx, record)
+}
@@ -0,0 +1,154 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestComplianceWebhookHandler(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var dataRequest *CustomersDataRequestPayload
+	var customersRedact *CustomersRedactPayload
+	var records []ComplianceAuditRecord
+
+	h := NewComplianceWebhookHandler(app, ComplianceHandlers{
+		CustomersDataRequest: func(ctx context.Context, payload *CustomersDataRequestPayload) error {
+			dataRequest = payload
+			return nil
+		},
+		CustomersRedact: func(ctx context.Context, payload *CustomersRedactPayload) error {
+			customersRedact = payload
+			return nil
+		},
+		ShopRedact: func(ctx context.Context, payload *ShopRedactPayload) error {
+			return errors.New("database down")
+		},
+		Audit: func(ctx context.Context, record ComplianceAuditRecord) {
+			records = append(records, record)
+		},
+	})
+
+	cases := []struct {
+		topic    string
+		body     string
+		expected int
+	}{
+		{
+			WebhookTopicCustomersDataRequest,
+			`{"shop_id":954889,"shop_domain":"fooshop.myshopify.com","orders_requested":[299938,280263],"customer":{"id":191167,"email":"john@example.com","phone":"555-625-1199"},"data_request":{"id":9999}}`,
+			http.StatusOK,
+		},
+		{
+			WebhookTopicCustomersRedact,
+			`{"shop_id":954889,"shop_domain":"fooshop.myshopify.com","customer":{"id":191167,"email":"john@example.com","phone":"555-625-1199"},"orders_to_redact":[299938,280263]}`,
+			http.StatusOK,
+		},
+		{
+			WebhookTopicShopRedact,
+			`{"shop_id":954889,"shop_domain":"fooshop.myshopify.com"}`,
+			http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(c.topic, c.body, app.ApiSecret))
+		if w.Code != c.expected {
+			t.Errorf("ComplianceWebhookHandler %s responded %d, expected %d", c.topic, w.Code, c.expected)
+		}
+	}
+
+	expectedDataRequest := &CustomersDataRequestPayload{
+		ShopId:          954889,
+		ShopDomain:      "fooshop.myshopify.com",
+		OrdersRequested: []uint64{299938, 280263},
+		Customer:        ComplianceCustomer{Id: 191167, Email: "john@example.com", Phone: "555-625-1199"},
+	}
+	expectedDataRequest.DataRequest.Id = 9999
+	if !reflect.DeepEqual(dataRequest, expectedDataRequest) {
+		t.Errorf("CustomersDataRequest handler received %+v, expected %+v", dataRequest, expectedDataRequest)
+	}
+
+	if customersRedact == nil || !reflect.DeepEqual(customersRedact.OrdersToRedact, []uint64{299938, 280263}) {
+		t.Errorf("CustomersRedact handler received %+v", customersRedact)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Audit received %d records, expected 3", len(records))
+	}
+	for _, r := range records[:2] {
+		if r.Err != nil || r.FulfilledAt.IsZero() || r.CustomerId != 191167 || r.ShopId != 954889 || r.ShopDomain != "fooshop.myshopify.com" {
+			t.Errorf("Audit received %+v, expected a fulfilled request", r)
+		}
+	}
+	if r := records[2]; r.Topic != WebhookTopicShopRedact || r.Err == nil || !r.FulfilledAt.IsZero() {
+		t.Errorf("Audit received %+v, expected a failed shop/redact", r)
+	}
+}
+
+func TestComplianceWebhookHandlerUnverified(t *testing.T) {
+	setup()
+	defer teardown()
+
+	called := false
+	h := NewComplianceWebhookHandler(app, ComplianceHandlers{
+		ShopRedact: func(ctx context.Context, payload *ShopRedactPayload) error {
+			called = true
+			return nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newWebhookRequest(WebhookTopicShopRedact, `{"shop_id":954889}`, "wrong secret"))
+	if w.Code != http.StatusUnauthorized || called {
+		t.Errorf("ComplianceWebhookHandler responded %d to an unverified request, expected %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestComplianceWebhookHandlerRegisteredPayloadType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	RegisterWebhookTopic(WebhookTopicCustomersRedact, nil)
+	defer RegisterWebhookTopic(WebhookTopicCustomersRedact, CustomersRedactPayload{})
+
+	var customersRedact *CustomersRedactPayload
+	var records []ComplianceAuditRecord
+	h := NewComplianceWebhookHandler(app, ComplianceHandlers{
+		CustomersRedact: func(ctx context.Context, payload *CustomersRedactPayload) error {
+			customersRedact = payload
+			return nil
+		},
+		Audit: func(ctx context.Context, record ComplianceAuditRecord) {
+			records = append(records, record)
+		},
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newWebhookRequest(WebhookTopicCustomersRedact, `{"shop_id":954889,"customer":{"id":191167}}`, app.ApiSecret))
+	if w.Code != http.StatusOK {
+		t.Errorf("ComplianceWebhookHandler responded %d, expected %d", w.Code, http.StatusOK)
+	}
+	if customersRedact == nil || customersRedact.Customer.Id != 191167 {
+		t.Errorf("CustomersRedact handler received %+v", customersRedact)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newWebhookRequest(WebhookTopicCustomersRedact, `{"shop_id":"not a number"}`, app.ApiSecret))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("ComplianceWebhookHandler responded %d to an invalid payload, expected %d", w.Code, http.StatusBadRequest)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Audit received %d records, expected 2", len(records))
+	}
+	if r := records[1]; r.Err == nil || r.ReceivedAt.IsZero() || !r.FulfilledAt.IsZero() {
+		t.Errorf("Audit received %+v, expected a failed request with its reception time", r)
+	}
+}
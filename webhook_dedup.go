@@ -0,0 +1,110 @@
+package goshopify
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// WebhookTriggeredAtHeader is the header holding the time Shopify triggered
+// a webhook delivery, the same for all retries of that delivery.
+const WebhookTriggeredAtHeader = "X-Shopify-Triggered-At"
+
+const defaultWebhookDedupTTL = 48 * time.Hour
+
+// WebhookDeliveryStore records the ids of processed webhook deliveries so
+// that WebhookHandler can drop duplicates. Implementations shared by several
+// processes, e.g. backed by Redis, must make Add atomic.
+type WebhookDeliveryStore interface {
+	// Add records id for ttl. It returns false if id was already recorded
+	// and has not expired.
+	Add(ctx context.Context, id string, ttl time.Duration) (bool, error)
+
+	// Remove forgets id, so that a retry of a failed delivery is processed.
+	Remove(ctx context.Context, id string) error
+}
+
+// MemoryWebhookStore is an in-memory WebhookDeliveryStore keeping the most
+// recently added ids up to a capacity. It is safe for concurrent use, but
+// only deduplicates deliveries received by the same process.
+type MemoryWebhookStore struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently added
+
+	// test hook
+	now func() time.Time
+}
+
+type memoryWebhookEntry struct {
+	id      string
+	expires time.Time
+}
+
+// NewMemoryWebhookStore returns a MemoryWebhookStore holding up to capacity
+// ids, evicting the least recently added one when full. A capacity of zero
+// or less means no limit.
+func NewMemoryWebhookStore(capacity int) *MemoryWebhookStore {
+	return &MemoryWebhookStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Add records id for ttl, see WebhookDeliveryStore.
+func (s *MemoryWebhookStore) Add(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if e, ok := s.entries[id]; ok {
+		entry := e.Value.(*memoryWebhookEntry)
+		if now.Before(entry.expires) {
+			return false, nil
+		}
+		s.remove(e)
+	}
+
+	s.entries[id] = s.order.PushFront(&memoryWebhookEntry{id: id, expires: now.Add(ttl)})
+
+	// drop expired entries from the back, then evict down to capacity
+	for e := s.order.Back(); e != nil; e = s.order.Back() {
+		expired := !now.Before(e.Value.(*memoryWebhookEntry).expires)
+		if !expired && (s.capacity <= 0 || s.order.Len() <= s.capacity) {
+			break
+		}
+		s.remove(e)
+	}
+
+	return true, nil
+}
+
+// Remove forgets id, see WebhookDeliveryStore.
+func (s *MemoryWebhookStore) Remove(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[id]; ok {
+		s.remove(e)
+	}
+	return nil
+}
+
+// Len returns the number of ids held, including expired ones not yet
+// evicted.
+func (s *MemoryWebhookStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+func (s *MemoryWebhookStore) remove(e *list.Element) {
+	s.order.Remove(e)
+	delete(s.entries, e.Value.(*memoryWebhookEntry).id)
+}
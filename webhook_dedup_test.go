@@ -0,0 +1,122 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryWebhookStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 3, 29, 18, 0, 0, 0, time.UTC)
+
+	s := NewMemoryWebhookStore(2)
+	s.now = func() time.Time { return now }
+
+	if added, _ := s.Add(ctx, "a", time.Minute); !added {
+		t.Errorf("MemoryWebhookStore.Add(a) returned false for a new id")
+	}
+	if added, _ := s.Add(ctx, "a", time.Minute); added {
+		t.Errorf("MemoryWebhookStore.Add(a) returned true for a duplicate")
+	}
+
+	// expired ids are added again
+	now = now.Add(time.Minute)
+	if added, _ := s.Add(ctx, "a", time.Minute); !added {
+		t.Errorf("MemoryWebhookStore.Add(a) returned false for an expired id")
+	}
+
+	// the least recently added id is evicted beyond capacity
+	s.Add(ctx, "b", time.Minute)
+	s.Add(ctx, "c", time.Minute)
+	if s.Len() != 2 {
+		t.Errorf("MemoryWebhookStore.Len() returned %d, expected 2", s.Len())
+	}
+	if added, _ := s.Add(ctx, "a", time.Minute); !added {
+		t.Errorf("MemoryWebhookStore.Add(a) returned false for an evicted id")
+	}
+
+	s.Remove(ctx, "c")
+	if added, _ := s.Add(ctx, "c", time.Minute); !added {
+		t.Errorf("MemoryWebhookStore.Add(c) returned false for a removed id")
+	}
+}
+
+func TestWebhookHandlerDeduplication(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	fail := true
+	h := NewWebhookHandler(app)
+	h.Store = NewMemoryWebhookStore(100)
+	h.HandleFunc("orders/create", func(ctx context.Context, event *WebhookEvent) error {
+		calls++
+		if fail {
+			return errors.New("database down")
+		}
+		return nil
+	})
+
+	deliver := func() int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest("orders/create", `{"id":1}`, app.ApiSecret))
+		return w.Code
+	}
+
+	// a failed delivery is processed again on retry
+	if code := deliver(); code != http.StatusInternalServerError {
+		t.Errorf("WebhookHandler responded %d, expected %d", code, http.StatusInternalServerError)
+	}
+	fail = false
+	if code := deliver(); code != http.StatusOK {
+		t.Errorf("WebhookHandler responded %d, expected %d", code, http.StatusOK)
+	}
+
+	// a processed delivery is acknowledged without calling the handler
+	if code := deliver(); code != http.StatusOK {
+		t.Errorf("WebhookHandler responded %d to a duplicate, expected %d", code, http.StatusOK)
+	}
+
+	if calls != 2 {
+		t.Errorf("WebhookHandler called the handler %d times, expected 2", calls)
+	}
+}
+
+func TestWebhookHandlerMaxAge(t *testing.T) {
+	setup()
+	defer teardown()
+
+	h := NewWebhookHandler(app)
+	h.MaxAge = 5 * time.Minute
+	h.HandleFunc("orders/create", func(ctx context.Context, event *WebhookEvent) error {
+		return nil
+	})
+
+	cases := []struct {
+		description string
+		triggeredAt string
+		expected    int
+	}{
+		{"recent", time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano), http.StatusOK},
+		{"stale", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano), http.StatusBadRequest},
+		{"missing", "", http.StatusBadRequest},
+		{"invalid", "yesterday", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		req := newWebhookRequest("orders/create", `{"id":1}`, app.ApiSecret)
+		if c.triggeredAt != "" {
+			req.Header.Set(WebhookTriggeredAtHeader, c.triggeredAt)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != c.expected {
+			t.Errorf("WebhookHandler %s delivery responded %d, expected %d", c.description, w.Code, c.expected)
+		}
+	}
+}
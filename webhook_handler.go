@@ -0,0 +1,230 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Headers of webhook deliveries sent by Shopify
+const (
+	WebhookTopicHeader      = "X-Shopify-Topic"
+	WebhookShopDomainHeader = "X-Shopify-Shop-Domain"
+	WebhookIdHeader         = "X-Shopify-Webhook-Id"
+	WebhookApiVersionHeader = "X-Shopify-API-Version"
+)
+
+// WebhookEvent is a verified webhook delivery
+type WebhookEvent struct {
+	Topic      string
+	ShopDomain string
+	WebhookId  string
+	ApiVersion string
+
+	// TriggeredAt is when Shopify triggered the delivery, zero if unknown
+	TriggeredAt time.Time
+
+	// Body is the raw payload
+	Body []byte
+
+	// Payload is the decoded body, see DecodeWebhookPayload, e.g. an *Order
+	// for "orders/create" or a *Product for "products/update".
+	Payload interface{}
+}
+
+// WebhookHandlerFunc handles the webhooks of a topic. Returning an error
+// responds with a 500 status so that Shopify retries the delivery.
+type WebhookHandlerFunc func(ctx context.Context, event *WebhookEvent) error
+
+// webhookDecodeError is returned by handler functions decoding the payload
+// themselves when it cannot be decoded, answered with DecodeErrorStatus.
+type webhookDecodeError struct {
+	err error
+}
+
+func (e webhookDecodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e webhookDecodeError) Unwrap() error {
+	return e.err
+}
+
+// WebhookHandler is an http.Handler receiving Shopify webhooks. It verifies
+// the HMAC of every delivery, decodes its payload and dispatches it to the
+// function registered for its topic.
+//
+//	h := goshopify.NewWebhookHandler(app)
+//	h.HandleFunc("orders/create", func(ctx context.Context, e *goshopify.WebhookEvent) error {
+//		order := e.Payload.(*goshopify.Order)
+//		// ...
+//		return nil
+//	})
+//	http.Handle("/webhooks", h)
+type WebhookHandler struct {
+	app App
+
+	mu       sync.RWMutex
+	handlers map[string]WebhookHandlerFunc
+
+	// UnknownTopicStatus is the response status for topics without a
+	// handler, defaults to 200 so that Shopify does not retry them.
+	UnknownTopicStatus int
+
+	// DecodeErrorStatus is the response status for payloads that cannot
+	// be decoded, defaults to 400.
+	DecodeErrorStatus int
+
+	// Store, if set, deduplicates deliveries by their webhook id. A
+	// delivery is recorded once it is dispatched, and forgotten again if
+	// its handler fails so that Shopify's retry is processed. Duplicates
+	// get a 200 response without calling the handler.
+	Store WebhookDeliveryStore
+
+	// DedupTTL is how long Store remembers a delivery, defaults to 48
+	// hours, the time Shopify keeps retrying a failed delivery.
+	DedupTTL time.Duration
+
+	// MaxAge, if set, rejects deliveries triggered longer ago with
+	// StaleStatus, so that captured requests cannot be replayed later.
+	MaxAge time.Duration
+
+	// StaleStatus is the response status for deliveries older than
+	// MaxAge, defaults to 400.
+	StaleStatus int
+
+	// OnError, if set, is called with every failed delivery, e.g. to log
+	// it. The event is nil when the request could not be verified.
+	OnError func(r *http.Request, event *WebhookEvent, err error)
+}
+
+// NewWebhookHandler returns a WebhookHandler verifying deliveries with the
+// ApiSecret of app.
+func NewWebhookHandler(app App) *WebhookHandler {
+	return &WebhookHandler{
+		app:                app,
+		handlers:           make(map[string]WebhookHandlerFunc),
+		UnknownTopicStatus: http.StatusOK,
+		DecodeErrorStatus:  http.StatusBadRequest,
+		StaleStatus:        http.StatusBadRequest,
+	}
+}
+
+// HandleFunc registers fn for the webhooks of topic, e.g. "orders/create",
+// replacing any function registered before.
+func (h *WebhookHandler) HandleFunc(topic string, fn WebhookHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers[topic] = fn
+}
+
+func (h *WebhookHandler) handler(topic string) WebhookHandlerFunc {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.handlers[topic]
+}
+
+// ServeHTTP verifies, decodes and dispatches a webhook delivery. Requests
+// that fail verification get a 401 response.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if ok, err := h.app.VerifyWebhookRequestVerbose(r); !ok {
+		h.fail(w, r, nil, http.StatusUnauthorized, fmt.Errorf("webhook verification failed: %w", err))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.fail(w, r, nil, http.StatusBadRequest, err)
+		return
+	}
+
+	event := &WebhookEvent{
+		Topic:      r.Header.Get(WebhookTopicHeader),
+		ShopDomain: r.Header.Get(WebhookShopDomainHeader),
+		WebhookId:  r.Header.Get(WebhookIdHeader),
+		ApiVersion: r.Header.Get(WebhookApiVersionHeader),
+		Body:       body,
+	}
+	if triggeredAt := r.Header.Get(WebhookTriggeredAtHeader); triggeredAt != "" {
+		if event.TriggeredAt, err = time.Parse(time.RFC3339Nano, triggeredAt); err != nil {
+			h.fail(w, r, event, http.StatusBadRequest, fmt.Errorf("webhook %s header: %w", WebhookTriggeredAtHeader, err))
+			return
+		}
+	}
+
+	if h.MaxAge > 0 {
+		if event.TriggeredAt.IsZero() {
+			h.fail(w, r, event, h.StaleStatus, fmt.Errorf("webhook %s header not set", WebhookTriggeredAtHeader))
+			return
+		}
+		if age := time.Since(event.TriggeredAt); age > h.MaxAge {
+			h.fail(w, r, event, h.StaleStatus, fmt.Errorf("webhook %s triggered %s ago", event.WebhookId, age.Round(time.Second)))
+			return
+		}
+	}
+
+	fn := h.handler(event.Topic)
+	if fn == nil {
+		w.WriteHeader(h.UnknownTopicStatus)
+		return
+	}
+
+	event.Payload, err = DecodeWebhookPayload(event.Topic, body)
+	if err != nil {
+		h.fail(w, r, event, h.DecodeErrorStatus, fmt.Errorf("webhook %s payload: %w", event.Topic, err))
+		return
+	}
+
+	dedup := h.Store != nil && event.WebhookId != ""
+	if dedup {
+		ttl := h.DedupTTL
+		if ttl <= 0 {
+			ttl = defaultWebhookDedupTTL
+		}
+		added, err := h.Store.Add(r.Context(), event.WebhookId, ttl)
+		if err != nil {
+			h.fail(w, r, event, http.StatusInternalServerError, err)
+			return
+		}
+		if !added {
+			// already processed, acknowledge so that Shopify stops retrying
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	if err := fn(r.Context(), event); err != nil {
+		if dedup {
+			if rmErr := h.Store.Remove(r.Context(), event.WebhookId); rmErr != nil {
+				err = fmt.Errorf("%w (removing delivery from store: %v)", err, rmErr)
+			}
+		}
+		status := http.StatusInternalServerError
+		if errors.As(err, &webhookDecodeError{}) {
+			status = h.DecodeErrorStatus
+		}
+		h.fail(w, r, event, status, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) fail(w http.ResponseWriter, r *http.Request, event *WebhookEvent, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(r, event, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,147 @@
+package goshopify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newWebhookRequest(topic, body, secret string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req.Header.Set("X-Shopify-Hmac-Sha256", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	req.Header.Set(WebhookTopicHeader, topic)
+	req.Header.Set(WebhookShopDomainHeader, "fooshop.myshopify.com")
+	req.Header.Set(WebhookIdHeader, "b54557e4-bdd9-4b37-8a5f-bf7d70bcd043")
+	req.Header.Set(WebhookApiVersionHeader, testApiVersion)
+	return req
+}
+
+func TestWebhookHandlerDispatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	h := NewWebhookHandler(app)
+
+	var received *WebhookEvent
+	h.HandleFunc("orders/create", func(ctx context.Context, event *WebhookEvent) error {
+		received = event
+		return nil
+	})
+	h.HandleFunc("products/update", func(ctx context.Context, event *WebhookEvent) error {
+		t.Errorf("products/update handler called for an orders/create webhook")
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newWebhookRequest("orders/create", `{"id":450789469,"name":"#1001","line_items":[{"id":466157049}]}`, app.ApiSecret))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("WebhookHandler responded %d, expected %d", w.Code, http.StatusOK)
+	}
+	if received == nil {
+		t.Fatal("WebhookHandler did not call the orders/create handler")
+	}
+
+	if received.Topic != "orders/create" || received.ShopDomain != "fooshop.myshopify.com" ||
+		received.WebhookId != "b54557e4-bdd9-4b37-8a5f-bf7d70bcd043" || received.ApiVersion != testApiVersion {
+		t.Errorf("WebhookHandler passed event %+v", received)
+	}
+
+	order, ok := received.Payload.(*Order)
+	if !ok {
+		t.Fatalf("WebhookEvent.Payload is %T, expected *Order", received.Payload)
+	}
+	if order.Id != 450789469 || order.Name != "#1001" || len(order.LineItems) != 1 {
+		t.Errorf("WebhookEvent.Payload was not decoded: %+v", order)
+	}
+}
+
+func TestWebhookHandlerPayloadTypes(t *testing.T) {
+	cases := []struct {
+		topic    string
+		expected string
+	}{
+		{"products/create", "*goshopify.Product"},
+		{"customers/update", "*goshopify.Customer"},
+		{"fulfillments/create", "*goshopify.Fulfillment"},
+		{"app/uninstalled", "*goshopify.Shop"},
+		{"carts/create", "*map[string]interface {}"},
+	}
+
+	for _, c := range cases {
+		payload, err := DecodeWebhookPayload(c.topic, []byte(`{"id":1}`))
+		if err != nil {
+			t.Fatalf("DecodeWebhookPayload(%s) returned %v", c.topic, err)
+		}
+		if actual := reflect.TypeOf(payload).String(); actual != c.expected {
+			t.Errorf("DecodeWebhookPayload(%s) returned %s, expected %s", c.topic, actual, c.expected)
+		}
+	}
+}
+
+func TestWebhookHandlerErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	h := NewWebhookHandler(app)
+	h.HandleFunc("orders/create", func(ctx context.Context, event *WebhookEvent) error {
+		return errors.New("database down")
+	})
+	h.HandleFunc("products/create", func(ctx context.Context, event *WebhookEvent) error {
+		return nil
+	})
+
+	var errs []error
+	h.OnError = func(r *http.Request, event *WebhookEvent, err error) {
+		errs = append(errs, err)
+	}
+
+	cases := []struct {
+		description string
+		req         *http.Request
+		expected    int
+	}{
+		{"invalid hmac", newWebhookRequest("orders/create", `{"id":1}`, "wrong secret"), http.StatusUnauthorized},
+		{"handler error", newWebhookRequest("orders/create", `{"id":1}`, app.ApiSecret), http.StatusInternalServerError},
+		{"decode error", newWebhookRequest("products/create", `{"id":"not a number"}`, app.ApiSecret), http.StatusBadRequest},
+		{"unknown topic", newWebhookRequest("carts/create", `{"id":1}`, app.ApiSecret), http.StatusOK},
+		{"wrong method", httptest.NewRequest(http.MethodGet, "/webhooks", nil), http.StatusMethodNotAllowed},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, c.req)
+		if w.Code != c.expected {
+			t.Errorf("WebhookHandler %s responded %d, expected %d", c.description, w.Code, c.expected)
+		}
+	}
+
+	if len(errs) != 3 {
+		t.Errorf("WebhookHandler.OnError called %d times, expected 3", len(errs))
+	}
+
+	h.UnknownTopicStatus = http.StatusNotFound
+	h.DecodeErrorStatus = http.StatusOK
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newWebhookRequest("carts/create", `{"id":1}`, app.ApiSecret))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("WebhookHandler unknown topic responded %d, expected %d", w.Code, http.StatusNotFound)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newWebhookRequest("products/create", `{"id":"not a number"}`, app.ApiSecret))
+	if w.Code != http.StatusOK {
+		t.Errorf("WebhookHandler decode error responded %d, expected %d", w.Code, http.StatusOK)
+	}
+}
@@ -2,8 +2,13 @@ package goshopify
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"path"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -172,3 +177,189 @@ func TestWebhookDelete(t *testing.T) {
 		t.Errorf("Webhook.Delete returned error: %v", err)
 	}
 }
+
+func registerWebhookSync(t *testing.T) map[string]int {
+	t.Helper()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("https://fooshop.myshopify.com/%s/webhooks.json?limit=250", client.pathPrefix),
+		httpmock.NewStringResponder(200, `{"webhooks":[
+			{"id":1,"topic":"orders/create","address":"https://example.com/webhooks","format":"json","fields":["updated_at","id"]},
+			{"id":2,"topic":"products/update","address":"https://old.example.com/webhooks","format":"json"},
+			{"id":3,"topic":"app/uninstalled","address":"https://example.com/webhooks","format":"json"}
+		]}`))
+
+	calls := map[string]int{}
+	httpmock.RegisterResponder("POST", fmt.Sprintf("https://fooshop.myshopify.com/%s/webhooks.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			calls["POST"]++
+			return httpmock.NewStringResponse(201, `{"webhook":{"id":4,"topic":"customers/create","address":"https://example.com/webhooks","format":"json"}}`), nil
+		})
+	httpmock.RegisterResponder("PUT", fmt.Sprintf("https://fooshop.myshopify.com/%s/webhooks/2.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			calls["PUT"]++
+			return httpmock.NewStringResponse(200, `{"webhook":{"id":2,"topic":"products/update","address":"https://example.com/webhooks","format":"json"}}`), nil
+		})
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("https://fooshop.myshopify.com/%s/webhooks/3.json", client.pathPrefix),
+		func(req *http.Request) (*http.Response, error) {
+			calls["DELETE"]++
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+
+	return calls
+}
+
+var desiredWebhooks = []Webhook{
+	{Topic: "orders/create", Address: "https://example.com/webhooks", Fields: []string{"id", "updated_at"}},
+	{Topic: "products/update", Address: "https://example.com/webhooks"},
+	{Topic: "customers/create", Address: "https://example.com/webhooks"},
+}
+
+func webhookIds(webhooks []Webhook) []uint64 {
+	ids := []uint64{}
+	for _, w := range webhooks {
+		ids = append(ids, w.Id)
+	}
+	return ids
+}
+
+func TestWebhookSync(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := registerWebhookSync(t)
+
+	report, err := client.Webhook.Sync(context.Background(), desiredWebhooks, &WebhookSyncOptions{DeleteExtra: true})
+	if err != nil {
+		t.Fatalf("Webhook.Sync returned error: %v", err)
+	}
+
+	expectedCalls := map[string]int{"POST": 1, "PUT": 1, "DELETE": 1}
+	if !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("Webhook.Sync made calls %v, expected %v", calls, expectedCalls)
+	}
+
+	actual := [][]uint64{webhookIds(report.Created), webhookIds(report.Updated), webhookIds(report.Deleted), webhookIds(report.Unchanged), webhookIds(report.Extra)}
+	expected := [][]uint64{{4}, {2}, {3}, {1}, {}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Webhook.Sync reported created, updated, deleted, unchanged and extra ids %v, expected %v", actual, expected)
+	}
+}
+
+func TestWebhookSyncDryRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := registerWebhookSync(t)
+
+	report, err := client.Webhook.Sync(context.Background(), desiredWebhooks, &WebhookSyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Webhook.Sync returned error: %v", err)
+	}
+
+	if len(calls) != 0 {
+		t.Errorf("Webhook.Sync made calls %v in a dry run", calls)
+	}
+
+	if !report.DryRun {
+		t.Errorf("Webhook.Sync report is not marked as a dry run")
+	}
+	if len(report.Created) != 1 || report.Created[0].Topic != "customers/create" {
+		t.Errorf("Webhook.Sync reported created %+v", report.Created)
+	}
+	if len(report.Updated) != 1 || report.Updated[0].Id != 2 || report.Updated[0].Address != "https://example.com/webhooks" {
+		t.Errorf("Webhook.Sync reported updated %+v", report.Updated)
+	}
+	if len(report.Deleted) != 0 || len(report.Extra) != 1 || report.Extra[0].Id != 3 {
+		t.Errorf("Webhook.Sync reported deleted %+v and extra %+v", report.Deleted, report.Extra)
+	}
+}
+
+func TestWebhookSyncSettles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// the shop's webhooks, updated by the calls of Sync
+	webhooks := map[uint64]Webhook{
+		1: {Id: 1, Topic: "orders/create", Address: "https://example.com/webhooks", Format: "xml"},
+		2: {Id: 2, Topic: "products/update", Address: "https://old.example.com/webhooks", Format: "xml"},
+		3: {Id: 3, Topic: "app/uninstalled", Address: "https://example.com/webhooks", Format: "json"},
+	}
+	nextId := uint64(4)
+	calls := map[string]int{}
+
+	webhooksURL := fmt.Sprintf("https://fooshop.myshopify.com/%s/webhooks", client.pathPrefix)
+	httpmock.RegisterResponder("GET", webhooksURL+".json?limit=250",
+		func(req *http.Request) (*http.Response, error) {
+			resource := WebhooksResource{Webhooks: []Webhook{}}
+			for id := uint64(1); id < nextId; id++ {
+				if w, ok := webhooks[id]; ok {
+					resource.Webhooks = append(resource.Webhooks, w)
+				}
+			}
+			return httpmock.NewJsonResponse(200, resource)
+		})
+	httpmock.RegisterResponder("POST", webhooksURL+".json",
+		func(req *http.Request) (*http.Response, error) {
+			calls["POST"]++
+			resource := WebhookResource{}
+			if err := json.NewDecoder(req.Body).Decode(&resource); err != nil {
+				return nil, err
+			}
+			w := *resource.Webhook
+			w.Id = nextId
+			if w.Format == "" {
+				w.Format = "json"
+			}
+			webhooks[w.Id] = w
+			nextId++
+			return httpmock.NewJsonResponse(201, WebhookResource{Webhook: &w})
+		})
+	httpmock.RegisterResponder("PUT", `=~^`+webhooksURL+`/\d+\.json`,
+		func(req *http.Request) (*http.Response, error) {
+			calls["PUT"]++
+			resource := WebhookResource{}
+			if err := json.NewDecoder(req.Body).Decode(&resource); err != nil {
+				return nil, err
+			}
+			webhooks[resource.Webhook.Id] = *resource.Webhook
+			return httpmock.NewJsonResponse(200, resource)
+		})
+	httpmock.RegisterResponder("DELETE", `=~^`+webhooksURL+`/\d+\.json`,
+		func(req *http.Request) (*http.Response, error) {
+			calls["DELETE"]++
+			id, err := strconv.ParseUint(strings.TrimSuffix(path.Base(req.URL.Path), ".json"), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			delete(webhooks, id)
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+
+	options := &WebhookSyncOptions{DeleteExtra: true}
+	report, err := client.Webhook.Sync(context.Background(), desiredWebhooks, options)
+	if err != nil {
+		t.Fatalf("Webhook.Sync returned error: %v", err)
+	}
+	if len(report.Created) != 1 || len(report.Updated) != 2 || len(report.Deleted) != 1 {
+		t.Errorf("Webhook.Sync reported %+v", report)
+	}
+	if webhooks[2].Format != "xml" {
+		t.Errorf("Webhook.Sync changed the format of webhook 2 to %q", webhooks[2].Format)
+	}
+
+	calls = map[string]int{}
+	for _, dryRun := range []bool{true, false} {
+		options.DryRun = dryRun
+		report, err = client.Webhook.Sync(context.Background(), desiredWebhooks, options)
+		if err != nil {
+			t.Fatalf("Webhook.Sync returned error: %v", err)
+		}
+
+		if len(report.Created) != 0 || len(report.Updated) != 0 || len(report.Deleted) != 0 || len(report.Extra) != 0 || len(report.Unchanged) != 3 {
+			t.Errorf("Webhook.Sync with dry run %v reported %+v, expected only unchanged webhooks", dryRun, report)
+		}
+	}
+	if len(calls) != 0 {
+		t.Errorf("Webhook.Sync made calls %v once in sync", calls)
+	}
+}
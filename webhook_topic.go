@@ -0,0 +1,385 @@
+package goshopify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Webhook topics supported by the Shopify API.
+// See: https://shopify.dev/docs/api/admin-rest/latest/resources/webhook#event-topics
+const (
+	WebhookTopicAppUninstalled = "app/uninstalled"
+
+	WebhookTopicAppSubscriptionsUpdate = "app_subscriptions/update"
+
+	WebhookTopicBulkOperationsFinish = "bulk_operations/finish"
+
+	WebhookTopicCartsCreate = "carts/create"
+	WebhookTopicCartsUpdate = "carts/update"
+
+	WebhookTopicCheckoutsCreate = "checkouts/create"
+	WebhookTopicCheckoutsDelete = "checkouts/delete"
+	WebhookTopicCheckoutsUpdate = "checkouts/update"
+
+	WebhookTopicCollectionListingsAdd    = "collection_listings/add"
+	WebhookTopicCollectionListingsRemove = "collection_listings/remove"
+	WebhookTopicCollectionListingsUpdate = "collection_listings/update"
+
+	WebhookTopicCollectionsCreate = "collections/create"
+	WebhookTopicCollectionsDelete = "collections/delete"
+	WebhookTopicCollectionsUpdate = "collections/update"
+
+	WebhookTopicCustomerGroupsCreate = "customer_groups/create"
+	WebhookTopicCustomerGroupsDelete = "customer_groups/delete"
+	WebhookTopicCustomerGroupsUpdate = "customer_groups/update"
+
+	WebhookTopicCustomersCreate  = "customers/create"
+	WebhookTopicCustomersDelete  = "customers/delete"
+	WebhookTopicCustomersDisable = "customers/disable"
+	WebhookTopicCustomersEnable  = "customers/enable"
+	WebhookTopicCustomersUpdate  = "customers/update"
+
+	WebhookTopicCustomersEmailMarketingConsentUpdate = "customers_email_marketing_consent/update"
+
+	WebhookTopicCustomersMarketingConsentUpdate = "customers_marketing_consent/update"
+
+	WebhookTopicDisputesCreate = "disputes/create"
+	WebhookTopicDisputesUpdate = "disputes/update"
+
+	WebhookTopicDomainsCreate  = "domains/create"
+	WebhookTopicDomainsDestroy = "domains/destroy"
+	WebhookTopicDomainsUpdate  = "domains/update"
+
+	WebhookTopicDraftOrdersCreate = "draft_orders/create"
+	WebhookTopicDraftOrdersDelete = "draft_orders/delete"
+	WebhookTopicDraftOrdersUpdate = "draft_orders/update"
+
+	WebhookTopicFulfillmentEventsCreate = "fulfillment_events/create"
+	WebhookTopicFulfillmentEventsDelete = "fulfillment_events/delete"
+
+	WebhookTopicFulfillmentOrdersCancellationRequestAccepted        = "fulfillment_orders/cancellation_request_accepted"
+	WebhookTopicFulfillmentOrdersCancellationRequestRejected        = "fulfillment_orders/cancellation_request_rejected"
+	WebhookTopicFulfillmentOrdersCancellationRequestSubmitted       = "fulfillment_orders/cancellation_request_submitted"
+	WebhookTopicFulfillmentOrdersCancelled                          = "fulfillment_orders/cancelled"
+	WebhookTopicFulfillmentOrdersFulfillmentRequestAccepted         = "fulfillment_orders/fulfillment_request_accepted"
+	WebhookTopicFulfillmentOrdersFulfillmentRequestRejected         = "fulfillment_orders/fulfillment_request_rejected"
+	WebhookTopicFulfillmentOrdersFulfillmentRequestSubmitted        = "fulfillment_orders/fulfillment_request_submitted"
+	WebhookTopicFulfillmentOrdersFulfillmentServiceFailedToComplete = "fulfillment_orders/fulfillment_service_failed_to_complete"
+	WebhookTopicFulfillmentOrdersHoldReleased                       = "fulfillment_orders/hold_released"
+	WebhookTopicFulfillmentOrdersLineItemsPreparedForLocalDelivery  = "fulfillment_orders/line_items_prepared_for_local_delivery"
+	WebhookTopicFulfillmentOrdersLineItemsPreparedForPickup         = "fulfillment_orders/line_items_prepared_for_pickup"
+	WebhookTopicFulfillmentOrdersMoved                              = "fulfillment_orders/moved"
+	WebhookTopicFulfillmentOrdersOrderRoutingComplete               = "fulfillment_orders/order_routing_complete"
+	WebhookTopicFulfillmentOrdersPlacedOnHold                       = "fulfillment_orders/placed_on_hold"
+	WebhookTopicFulfillmentOrdersRescheduled                        = "fulfillment_orders/rescheduled"
+	WebhookTopicFulfillmentOrdersScheduledFulfillmentOrderReady     = "fulfillment_orders/scheduled_fulfillment_order_ready"
+
+	WebhookTopicFulfillmentsCreate = "fulfillments/create"
+	WebhookTopicFulfillmentsUpdate = "fulfillments/update"
+
+	WebhookTopicInventoryItemsCreate = "inventory_items/create"
+	WebhookTopicInventoryItemsDelete = "inventory_items/delete"
+	WebhookTopicInventoryItemsUpdate = "inventory_items/update"
+
+	WebhookTopicInventoryLevelsConnect    = "inventory_levels/connect"
+	WebhookTopicInventoryLevelsDisconnect = "inventory_levels/disconnect"
+	WebhookTopicInventoryLevelsUpdate     = "inventory_levels/update"
+
+	WebhookTopicLocalesCreate = "locales/create"
+	WebhookTopicLocalesUpdate = "locales/update"
+
+	WebhookTopicLocationsActivate   = "locations/activate"
+	WebhookTopicLocationsCreate     = "locations/create"
+	WebhookTopicLocationsDeactivate = "locations/deactivate"
+	WebhookTopicLocationsDelete     = "locations/delete"
+	WebhookTopicLocationsUpdate     = "locations/update"
+
+	WebhookTopicMarketsCreate = "markets/create"
+	WebhookTopicMarketsDelete = "markets/delete"
+	WebhookTopicMarketsUpdate = "markets/update"
+
+	WebhookTopicOrderTransactionsCreate = "order_transactions/create"
+
+	WebhookTopicOrdersCancelled          = "orders/cancelled"
+	WebhookTopicOrdersCreate             = "orders/create"
+	WebhookTopicOrdersDelete             = "orders/delete"
+	WebhookTopicOrdersEdited             = "orders/edited"
+	WebhookTopicOrdersFulfilled          = "orders/fulfilled"
+	WebhookTopicOrdersPaid               = "orders/paid"
+	WebhookTopicOrdersPartiallyFulfilled = "orders/partially_fulfilled"
+	WebhookTopicOrdersUpdated            = "orders/updated"
+
+	WebhookTopicPaymentTermsCreate = "payment_terms/create"
+	WebhookTopicPaymentTermsDelete = "payment_terms/delete"
+	WebhookTopicPaymentTermsUpdate = "payment_terms/update"
+
+	WebhookTopicProductListingsAdd    = "product_listings/add"
+	WebhookTopicProductListingsRemove = "product_listings/remove"
+	WebhookTopicProductListingsUpdate = "product_listings/update"
+
+	WebhookTopicProductsCreate = "products/create"
+	WebhookTopicProductsDelete = "products/delete"
+	WebhookTopicProductsUpdate = "products/update"
+
+	WebhookTopicProfilesCreate = "profiles/create"
+	WebhookTopicProfilesDelete = "profiles/delete"
+	WebhookTopicProfilesUpdate = "profiles/update"
+
+	WebhookTopicRefundsCreate = "refunds/create"
+
+	WebhookTopicScheduledProductListingsAdd    = "scheduled_product_listings/add"
+	WebhookTopicScheduledProductListingsRemove = "scheduled_product_listings/remove"
+	WebhookTopicScheduledProductListingsUpdate = "scheduled_product_listings/update"
+
+	WebhookTopicSellingPlanGroupsCreate = "selling_plan_groups/create"
+	WebhookTopicSellingPlanGroupsDelete = "selling_plan_groups/delete"
+	WebhookTopicSellingPlanGroupsUpdate = "selling_plan_groups/update"
+
+	WebhookTopicShopUpdate = "shop/update"
+
+	WebhookTopicSubscriptionBillingAttemptsChallenged = "subscription_billing_attempts/challenged"
+	WebhookTopicSubscriptionBillingAttemptsFailure    = "subscription_billing_attempts/failure"
+	WebhookTopicSubscriptionBillingAttemptsSuccess    = "subscription_billing_attempts/success"
+
+	WebhookTopicSubscriptionContractsCreate = "subscription_contracts/create"
+	WebhookTopicSubscriptionContractsUpdate = "subscription_contracts/update"
+
+	WebhookTopicTenderTransactionsCreate = "tender_transactions/create"
+
+	WebhookTopicThemesCreate  = "themes/create"
+	WebhookTopicThemesDelete  = "themes/delete"
+	WebhookTopicThemesPublish = "themes/publish"
+	WebhookTopicThemesUpdate  = "themes/update"
+
+	WebhookTopicVariantsInStock    = "variants/in_stock"
+	WebhookTopicVariantsOutOfStock = "variants/out_of_stock"
+)
+
+// ErrUnknownWebhookTopic is returned when creating or updating a webhook with
+// a topic missing from the registry, see RegisterWebhookTopic.
+var ErrUnknownWebhookTopic = errors.New("unknown webhook topic")
+
+// payload type of topics without a matching type in the package
+var webhookRawPayloadType = reflect.TypeOf(map[string]interface{}{})
+
+var webhookTopics = struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+}{
+	types: map[string]reflect.Type{
+		WebhookTopicAppUninstalled: reflect.TypeOf(Shop{}),
+
+		WebhookTopicAppSubscriptionsUpdate: webhookRawPayloadType,
+
+		WebhookTopicBulkOperationsFinish: webhookRawPayloadType,
+
+		WebhookTopicCartsCreate: webhookRawPayloadType,
+		WebhookTopicCartsUpdate: webhookRawPayloadType,
+
+		WebhookTopicCheckoutsCreate: reflect.TypeOf(AbandonedCheckout{}),
+		WebhookTopicCheckoutsDelete: reflect.TypeOf(AbandonedCheckout{}),
+		WebhookTopicCheckoutsUpdate: reflect.TypeOf(AbandonedCheckout{}),
+
+		WebhookTopicCollectionListingsAdd:    webhookRawPayloadType,
+		WebhookTopicCollectionListingsRemove: webhookRawPayloadType,
+		WebhookTopicCollectionListingsUpdate: webhookRawPayloadType,
+
+		WebhookTopicCollectionsCreate: reflect.TypeOf(Collection{}),
+		WebhookTopicCollectionsDelete: reflect.TypeOf(Collection{}),
+		WebhookTopicCollectionsUpdate: reflect.TypeOf(Collection{}),
+
+		WebhookTopicCustomerGroupsCreate: webhookRawPayloadType,
+		WebhookTopicCustomerGroupsDelete: webhookRawPayloadType,
+		WebhookTopicCustomerGroupsUpdate: webhookRawPayloadType,
+
+		WebhookTopicCustomersDataRequest: reflect.TypeOf(CustomersDataRequestPayload{}),
+		WebhookTopicCustomersRedact:      reflect.TypeOf(CustomersRedactPayload{}),
+		WebhookTopicShopRedact:           reflect.TypeOf(ShopRedactPayload{}),
+
+		WebhookTopicCustomersCreate:  reflect.TypeOf(Customer{}),
+		WebhookTopicCustomersDelete:  reflect.TypeOf(Customer{}),
+		WebhookTopicCustomersDisable: reflect.TypeOf(Customer{}),
+		WebhookTopicCustomersEnable:  reflect.TypeOf(Customer{}),
+		WebhookTopicCustomersUpdate:  reflect.TypeOf(Customer{}),
+
+		WebhookTopicCustomersEmailMarketingConsentUpdate: webhookRawPayloadType,
+
+		WebhookTopicCustomersMarketingConsentUpdate: webhookRawPayloadType,
+
+		WebhookTopicDisputesCreate: webhookRawPayloadType,
+		WebhookTopicDisputesUpdate: webhookRawPayloadType,
+
+		WebhookTopicDomainsCreate:  webhookRawPayloadType,
+		WebhookTopicDomainsDestroy: webhookRawPayloadType,
+		WebhookTopicDomainsUpdate:  webhookRawPayloadType,
+
+		WebhookTopicDraftOrdersCreate: reflect.TypeOf(DraftOrder{}),
+		WebhookTopicDraftOrdersDelete: reflect.TypeOf(DraftOrder{}),
+		WebhookTopicDraftOrdersUpdate: reflect.TypeOf(DraftOrder{}),
+
+		WebhookTopicFulfillmentEventsCreate: reflect.TypeOf(FulfillmentEvent{}),
+		WebhookTopicFulfillmentEventsDelete: reflect.TypeOf(FulfillmentEvent{}),
+
+		WebhookTopicFulfillmentOrdersCancellationRequestAccepted:        webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersCancellationRequestRejected:        webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersCancellationRequestSubmitted:       webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersCancelled:                          webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersFulfillmentRequestAccepted:         webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersFulfillmentRequestRejected:         webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersFulfillmentRequestSubmitted:        webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersFulfillmentServiceFailedToComplete: webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersHoldReleased:                       webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersLineItemsPreparedForLocalDelivery:  webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersLineItemsPreparedForPickup:         webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersMoved:                              webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersOrderRoutingComplete:               webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersPlacedOnHold:                       webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersRescheduled:                        webhookRawPayloadType,
+		WebhookTopicFulfillmentOrdersScheduledFulfillmentOrderReady:     webhookRawPayloadType,
+
+		WebhookTopicFulfillmentsCreate: reflect.TypeOf(Fulfillment{}),
+		WebhookTopicFulfillmentsUpdate: reflect.TypeOf(Fulfillment{}),
+
+		WebhookTopicInventoryItemsCreate: reflect.TypeOf(InventoryItem{}),
+		WebhookTopicInventoryItemsDelete: reflect.TypeOf(InventoryItem{}),
+		WebhookTopicInventoryItemsUpdate: reflect.TypeOf(InventoryItem{}),
+
+		WebhookTopicInventoryLevelsConnect:    reflect.TypeOf(InventoryLevel{}),
+		WebhookTopicInventoryLevelsDisconnect: reflect.TypeOf(InventoryLevel{}),
+		WebhookTopicInventoryLevelsUpdate:     reflect.TypeOf(InventoryLevel{}),
+
+		WebhookTopicLocalesCreate: webhookRawPayloadType,
+		WebhookTopicLocalesUpdate: webhookRawPayloadType,
+
+		WebhookTopicLocationsActivate:   reflect.TypeOf(Location{}),
+		WebhookTopicLocationsCreate:     reflect.TypeOf(Location{}),
+		WebhookTopicLocationsDeactivate: reflect.TypeOf(Location{}),
+		WebhookTopicLocationsDelete:     reflect.TypeOf(Location{}),
+		WebhookTopicLocationsUpdate:     reflect.TypeOf(Location{}),
+
+		WebhookTopicMarketsCreate: webhookRawPayloadType,
+		WebhookTopicMarketsDelete: webhookRawPayloadType,
+		WebhookTopicMarketsUpdate: webhookRawPayloadType,
+
+		WebhookTopicOrderTransactionsCreate: reflect.TypeOf(Transaction{}),
+
+		WebhookTopicOrdersCancelled:          reflect.TypeOf(Order{}),
+		WebhookTopicOrdersCreate:             reflect.TypeOf(Order{}),
+		WebhookTopicOrdersDelete:             reflect.TypeOf(Order{}),
+		WebhookTopicOrdersEdited:             webhookRawPayloadType,
+		WebhookTopicOrdersFulfilled:          reflect.TypeOf(Order{}),
+		WebhookTopicOrdersPaid:               reflect.TypeOf(Order{}),
+		WebhookTopicOrdersPartiallyFulfilled: reflect.TypeOf(Order{}),
+		WebhookTopicOrdersUpdated:            reflect.TypeOf(Order{}),
+
+		WebhookTopicPaymentTermsCreate: webhookRawPayloadType,
+		WebhookTopicPaymentTermsDelete: webhookRawPayloadType,
+		WebhookTopicPaymentTermsUpdate: webhookRawPayloadType,
+
+		WebhookTopicProductListingsAdd:    webhookRawPayloadType,
+		WebhookTopicProductListingsRemove: webhookRawPayloadType,
+		WebhookTopicProductListingsUpdate: webhookRawPayloadType,
+
+		WebhookTopicProductsCreate: reflect.TypeOf(Product{}),
+		WebhookTopicProductsDelete: reflect.TypeOf(Product{}),
+		WebhookTopicProductsUpdate: reflect.TypeOf(Product{}),
+
+		WebhookTopicProfilesCreate: webhookRawPayloadType,
+		WebhookTopicProfilesDelete: webhookRawPayloadType,
+		WebhookTopicProfilesUpdate: webhookRawPayloadType,
+
+		WebhookTopicRefundsCreate: reflect.TypeOf(Refund{}),
+
+		WebhookTopicScheduledProductListingsAdd:    webhookRawPayloadType,
+		WebhookTopicScheduledProductListingsRemove: webhookRawPayloadType,
+		WebhookTopicScheduledProductListingsUpdate: webhookRawPayloadType,
+
+		WebhookTopicSellingPlanGroupsCreate: webhookRawPayloadType,
+		WebhookTopicSellingPlanGroupsDelete: webhookRawPayloadType,
+		WebhookTopicSellingPlanGroupsUpdate: webhookRawPayloadType,
+
+		WebhookTopicShopUpdate: reflect.TypeOf(Shop{}),
+
+		WebhookTopicSubscriptionBillingAttemptsChallenged: webhookRawPayloadType,
+		WebhookTopicSubscriptionBillingAttemptsFailure:    webhookRawPayloadType,
+		WebhookTopicSubscriptionBillingAttemptsSuccess:    webhookRawPayloadType,
+
+		WebhookTopicSubscriptionContractsCreate: webhookRawPayloadType,
+		WebhookTopicSubscriptionContractsUpdate: webhookRawPayloadType,
+
+		WebhookTopicTenderTransactionsCreate: webhookRawPayloadType,
+
+		WebhookTopicThemesCreate:  reflect.TypeOf(Theme{}),
+		WebhookTopicThemesDelete:  reflect.TypeOf(Theme{}),
+		WebhookTopicThemesPublish: reflect.TypeOf(Theme{}),
+		WebhookTopicThemesUpdate:  reflect.TypeOf(Theme{}),
+
+		WebhookTopicVariantsInStock:    reflect.TypeOf(Variant{}),
+		WebhookTopicVariantsOutOfStock: reflect.TypeOf(Variant{}),
+	},
+}
+
+// RegisterWebhookTopic adds topic to the registry of webhook topics, or
+// replaces its payload type. The payload of its webhooks is decoded into a
+// new value of the type of payload, e.g. Order{} for "orders/create". Use it
+// for topics of newer API versions, or to decode a topic into your own type.
+func RegisterWebhookTopic(topic string, payload interface{}) {
+	t := reflect.TypeOf(payload)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		t = webhookRawPayloadType
+	}
+
+	webhookTopics.Lock()
+	defer webhookTopics.Unlock()
+
+	webhookTopics.types[topic] = t
+}
+
+// WebhookPayloadType returns the type the payload of topic is decoded into,
+// and whether topic is registered.
+func WebhookPayloadType(topic string) (reflect.Type, bool) {
+	webhookTopics.RLock()
+	defer webhookTopics.RUnlock()
+
+	t, ok := webhookTopics.types[topic]
+	return t, ok
+}
+
+// IsValidWebhookTopic reports whether topic is registered
+func IsValidWebhookTopic(topic string) bool {
+	_, ok := WebhookPayloadType(topic)
+	return ok
+}
+
+// DecodeWebhookPayload decodes the body of a webhook of topic into a new
+// value of the topic's payload type and returns a pointer to it, e.g. an
+// *Order for "orders/create". Payloads of unregistered topics are decoded
+// into a *map[string]interface{}.
+func DecodeWebhookPayload(topic string, body []byte) (interface{}, error) {
+	t, ok := WebhookPayloadType(topic)
+	if !ok {
+		t = webhookRawPayloadType
+	}
+
+	payload := reflect.New(t).Interface()
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func validateWebhookTopic(topic string) error {
+	if isComplianceWebhookTopic(topic) {
+		return fmt.Errorf("%w %q", ErrComplianceWebhookTopic, topic)
+	}
+	if !IsValidWebhookTopic(topic) {
+		return fmt.Errorf("%w %q", ErrUnknownWebhookTopic, topic)
+	}
+	return nil
+}
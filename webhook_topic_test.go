@@ -0,0 +1,89 @@
+package goshopify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestWebhookPayloadType(t *testing.T) {
+	cases := []struct {
+		topic    string
+		expected reflect.Type
+	}{
+		{WebhookTopicOrdersCreate, reflect.TypeOf(Order{})},
+		{WebhookTopicInventoryLevelsUpdate, reflect.TypeOf(InventoryLevel{})},
+		{WebhookTopicAppUninstalled, reflect.TypeOf(Shop{})},
+		{WebhookTopicCartsCreate, reflect.TypeOf(map[string]interface{}{})},
+	}
+
+	for _, c := range cases {
+		actual, ok := WebhookPayloadType(c.topic)
+		if !ok || actual != c.expected {
+			t.Errorf("WebhookPayloadType(%s) returned %v, %t, expected %v", c.topic, actual, ok, c.expected)
+		}
+	}
+
+	if IsValidWebhookTopic("order/create") {
+		t.Errorf("IsValidWebhookTopic(order/create) returned true")
+	}
+}
+
+func TestRegisterWebhookTopic(t *testing.T) {
+	type customPayload struct {
+		Id uint64 `json:"id"`
+	}
+
+	RegisterWebhookTopic("custom_topic/create", &customPayload{})
+	defer func() {
+		webhookTopics.Lock()
+		delete(webhookTopics.types, "custom_topic/create")
+		webhookTopics.Unlock()
+	}()
+
+	payload, err := DecodeWebhookPayload("custom_topic/create", []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf("DecodeWebhookPayload returned %v", err)
+	}
+
+	expected := &customPayload{Id: 1}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("DecodeWebhookPayload returned %#v, expected %#v", payload, expected)
+	}
+}
+
+func TestWebhookCreateUnknownTopic(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.Webhook.Create(context.Background(), Webhook{Topic: "order/create", Address: "https://example.com/webhooks"})
+	if !errors.Is(err, ErrUnknownWebhookTopic) {
+		t.Errorf("Webhook.Create returned %v, expected %v", err, ErrUnknownWebhookTopic)
+	}
+
+	_, err = client.Webhook.Update(context.Background(), Webhook{Id: 1, Topic: "order/create"})
+	if !errors.Is(err, ErrUnknownWebhookTopic) {
+		t.Errorf("Webhook.Update returned %v, expected %v", err, ErrUnknownWebhookTopic)
+	}
+
+	if n := httpmock.GetTotalCallCount(); n != 0 {
+		t.Errorf("Webhook.Create made %d calls with an unknown topic", n)
+	}
+}
+
+func TestWebhookCreateComplianceTopic(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.Webhook.Create(context.Background(), Webhook{Topic: WebhookTopicCustomersRedact, Address: "https://example.com/webhooks"})
+	if !errors.Is(err, ErrComplianceWebhookTopic) {
+		t.Errorf("Webhook.Create returned %v, expected %v", err, ErrComplianceWebhookTopic)
+	}
+
+	if n := httpmock.GetTotalCallCount(); n != 0 {
+		t.Errorf("Webhook.Create made %d calls with a compliance topic", n)
+	}
+}